@@ -40,8 +40,11 @@ package ctxlog
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"os"
+	"slices"
+	"sync"
 )
 
 // AttrExtractor defines a function that extracts one or more slog.Attr
@@ -51,8 +54,9 @@ type AttrExtractor func(ctx context.Context) []slog.Attr
 
 // config holds configuration options for ContextHandler.
 type config struct {
-	baseHandler slog.Handler
-	extractors  []AttrExtractor
+	baseHandler  slog.Handler
+	extractors   []AttrExtractor
+	dynamicLevel func(ctx context.Context) (slog.Level, bool)
 }
 
 // Option defines a functional option used to configure a ContextHandler.
@@ -67,18 +71,44 @@ func WithBaseHandler(h slog.Handler) Option {
 }
 
 // WithExtractor adds an AttrExtractor to ContextHandler. Multiple extractors
-// can be added and will all be applied to each log record.
+// can be added and will all be applied to each log record, in registration
+// order. When several extractors emit the same attribute key, the first one wins.
+// An extractor that panics is isolated: its attributes are replaced with a
+// "ctxlog_extractor_panic" attribute and the remaining extractors still run.
 func WithExtractor(ex AttrExtractor) Option {
 	return func(c *config) {
 		c.extractors = append(c.extractors, ex)
 	}
 }
 
+// WithDynamicLevel sets a function consulted by Enabled to override the minimum
+// level on a per-context basis, e.g. to emit debug logs for requests carrying
+// a "verbose" flag. When it returns true, records at or above the returned
+// level are enabled regardless of the base handler's own Enabled, which is
+// not consulted; otherwise Enabled delegates to the base handler as usual.
+// The standard library handlers do not filter again in Handle, so records
+// enabled this way are written, but custom base handlers that do may still
+// drop them.
+func WithDynamicLevel(f func(ctx context.Context) (slog.Level, bool)) Option {
+	return func(c *config) {
+		c.dynamicLevel = f
+	}
+}
+
 // ContextHandler is a slog.Handler that wraps another base handler and
 // automatically enriches log records with attributes extracted from a context.Context.
 type ContextHandler struct {
-	base       slog.Handler
-	extractors []AttrExtractor
+	base         slog.Handler
+	extractors   []AttrExtractor
+	dynamicLevel func(ctx context.Context) (slog.Level, bool)
+	// key identifies the extractors in attribute caches. It is shared by the
+	// handlers derived through WithAttrs and WithGroup.
+	key *handlerKey
+}
+
+// handlerKey is a unique identity used to key attribute caches.
+type handlerKey struct {
+	_ byte
 }
 
 // NewContextHandler creates a new ContextHandler with optional configuration
@@ -93,21 +123,34 @@ func NewContextHandler(opts ...Option) *ContextHandler {
 	}
 
 	return &ContextHandler{
-		base:       c.baseHandler,
-		extractors: c.extractors,
+		base:         c.baseHandler,
+		extractors:   c.extractors,
+		dynamicLevel: c.dynamicLevel,
+		key:          &handlerKey{},
 	}
 }
 
 // Enabled reports whether a log at the given level would be handled by the base handler.
-// Delegates to the underlying base handler.
+// Delegates to the underlying base handler, unless a dynamic level configured
+// with WithDynamicLevel applies to ctx.
 func (h *ContextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if h.dynamicLevel != nil {
+		if minLevel, ok := h.dynamicLevel(ctx); ok {
+			return level >= minLevel
+		}
+	}
 	return h.base.Enabled(ctx, level)
 }
 
 // Handle enriches the given slog.Record with attributes extracted from the context
-// and passes it to the base handler.
+// and passes it to the base handler. When no attribute is extracted, the
+// original record is passed through as is, without being copied.
 func (h *ContextHandler) Handle(ctx context.Context, rec slog.Record) error {
 	attrs := h.extractAttrs(ctx)
+	if len(attrs) == 0 {
+		return h.base.Handle(ctx, rec)
+	}
+
 	newRec := rec
 	newRec.AddAttrs(attrs...)
 
@@ -118,8 +161,10 @@ func (h *ContextHandler) Handle(ctx context.Context, rec slog.Record) error {
 // to every log record. The extractors remain unchanged.
 func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
 	return &ContextHandler{
-		base:       h.base.WithAttrs(attrs),
-		extractors: h.extractors,
+		base:         h.base.WithAttrs(attrs),
+		extractors:   h.extractors,
+		dynamicLevel: h.dynamicLevel,
+		key:          h.key,
 	}
 }
 
@@ -127,17 +172,97 @@ func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
 // the specified group name. The extractors remain unchanged.
 func (h *ContextHandler) WithGroup(name string) slog.Handler {
 	return &ContextHandler{
-		base:       h.base.WithGroup(name),
-		extractors: h.extractors,
+		base:         h.base.WithGroup(name),
+		extractors:   h.extractors,
+		dynamicLevel: h.dynamicLevel,
+		key:          h.key,
 	}
 }
 
-// extractAttrs applies all registered extractors to the given context and
-// returns the combined list of slog.Attr.
+// extractAttrs returns the attributes extracted from the given context,
+// reusing the ones cached in ctx by ContextWithCache, if any.
 func (h *ContextHandler) extractAttrs(ctx context.Context) []slog.Attr {
+	cache, ok := ctx.Value(cacheKey{}).(*attrCache)
+	if !ok {
+		return h.runExtractors(ctx)
+	}
+
+	cache.mu.Lock()
+	entry, found := cache.entries[h.key]
+	if !found {
+		entry = &cacheEntry{}
+		cache.entries[h.key] = entry
+	}
+	cache.mu.Unlock()
+
+	// concurrent log calls wait for the first one to run the extractors
+	entry.once.Do(func() {
+		entry.attrs = h.runExtractors(ctx)
+	})
+
+	return entry.attrs
+}
+
+// runExtractors applies all registered extractors to the given context and
+// returns the combined list of slog.Attr. Extractors are applied in
+// registration order and the first attribute emitted for a given key wins:
+// later attributes with the same key are dropped.
+func (h *ContextHandler) runExtractors(ctx context.Context) []slog.Attr {
 	var result []slog.Attr
 	for _, ex := range h.extractors {
-		result = append(result, ex(ctx)...)
+		for _, attr := range safeExtract(ctx, ex) {
+			if slices.ContainsFunc(result, func(a slog.Attr) bool { return a.Key == attr.Key }) {
+				continue
+			}
+			result = append(result, attr)
+		}
 	}
 	return result
 }
+
+// cacheKey is the context key under which the attribute cache is stored.
+type cacheKey struct{}
+
+// attrCache holds the attributes extracted for a context, per handler.
+type attrCache struct {
+	mu      sync.Mutex
+	entries map[*handlerKey]*cacheEntry
+}
+
+// cacheEntry holds the attributes extracted for a context by one handler.
+type cacheEntry struct {
+	once  sync.Once
+	attrs []slog.Attr
+}
+
+// ContextWithCache returns a copy of ctx carrying an attribute cache: the
+// extractors of a ContextHandler then run only once for that context, and
+// their result is reused by every subsequent log call, which reduces the
+// overhead in chatty handlers. Concurrent log calls wait for the first one to
+// run the extractors. It is typically called once per request, e.g. in a
+// middleware.
+//
+// The cache assumes that the values read by the extractors do not change:
+// contexts derived from the returned one share its cache, so values added
+// afterwards (e.g. with context.WithValue) are not seen by the extractors.
+// Call ContextWithCache again on the derived context to start a fresh cache.
+func ContextWithCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheKey{}, &attrCache{entries: make(map[*handlerKey]*cacheEntry)})
+}
+
+// extractorPanicKey is the key of the attribute emitted in place of the
+// attributes of an extractor that panicked.
+const extractorPanicKey = "ctxlog_extractor_panic"
+
+// safeExtract runs ex, recovering from any panic so that a buggy extractor
+// cannot break the logging call. In that case a single attribute holding the
+// recovered value is returned instead.
+func safeExtract(ctx context.Context, ex AttrExtractor) (attrs []slog.Attr) {
+	defer func() {
+		if r := recover(); r != nil {
+			attrs = []slog.Attr{slog.String(extractorPanicKey, fmt.Sprint(r))}
+		}
+	}()
+
+	return ex(ctx)
+}
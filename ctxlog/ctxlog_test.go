@@ -3,7 +3,11 @@ package ctxlog
 import (
 	"context"
 	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"gotest.tools/v3/assert"
 )
@@ -103,3 +107,234 @@ func TestContextHandlerWithAttrsWithGroup(t *testing.T) {
 	logger2.InfoContext(context.Background(), "Test WithGroup")
 	assert.Equal(t, len(th.records), 2)
 }
+
+func TestContextHandlerDuplicateKeysFirstWins(t *testing.T) {
+	th := &testHandler{}
+	handler := NewContextHandler(
+		WithBaseHandler(th),
+		WithExtractor(func(_ context.Context) []slog.Attr {
+			return []slog.Attr{slog.String("user", "first"), slog.String("user", "first-dup")}
+		}),
+		WithExtractor(func(_ context.Context) []slog.Attr {
+			return []slog.Attr{slog.String("user", "second"), slog.String("tenant", "acme")}
+		}),
+	)
+
+	logger := slog.New(handler)
+	logger.InfoContext(context.Background(), "Test duplicate keys")
+
+	assert.Equal(t, len(th.records), 1)
+
+	var attrs []string
+	th.records[0].Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, attr.String())
+		return true
+	})
+
+	assert.DeepEqual(t, attrs, []string{"user=first", "tenant=acme"})
+}
+
+func TestContextHandlerNoExtractedAttrs(t *testing.T) {
+	th := &testHandler{}
+	handler := NewContextHandler(
+		WithBaseHandler(th),
+		WithExtractor(func(_ context.Context) []slog.Attr {
+			return nil
+		}),
+	)
+
+	logger := slog.New(handler).With(slog.String("static", "value"))
+	logger.InfoContext(context.Background(), "Test no extracted attrs", slog.Int("n", 1))
+
+	assert.Equal(t, len(th.records), 1)
+	assert.Equal(t, th.records[0].Message, "Test no extracted attrs")
+
+	keys := map[string]string{}
+	th.records[0].Attrs(func(attr slog.Attr) bool {
+		keys[attr.Key] = attr.Value.String()
+		return true
+	})
+
+	assert.DeepEqual(t, keys, map[string]string{"n": "1"})
+}
+
+func TestContextHandlerExtractorPanic(t *testing.T) {
+	type userContextKey string
+
+	th := &testHandler{}
+	handler := NewContextHandler(
+		WithBaseHandler(th),
+		WithExtractor(func(ctx context.Context) []slog.Attr {
+			return []slog.Attr{slog.String("user", ctx.Value(userContextKey("user")).(string))}
+		}),
+		WithExtractor(func(_ context.Context) []slog.Attr {
+			return []slog.Attr{slog.String("tenant", "acme")}
+		}),
+	)
+
+	logger := slog.New(handler)
+	logger.InfoContext(context.Background(), "Test extractor panic")
+
+	assert.Equal(t, len(th.records), 1)
+	assert.Equal(t, th.records[0].Message, "Test extractor panic")
+
+	keys := map[string]string{}
+	th.records[0].Attrs(func(attr slog.Attr) bool {
+		keys[attr.Key] = attr.Value.String()
+		return true
+	})
+
+	assert.Equal(t, keys["tenant"], "acme")
+	assert.Assert(t, strings.Contains(keys[extractorPanicKey], "interface conversion"))
+}
+
+func TestContextHandlerDynamicLevel(t *testing.T) {
+	type verboseContextKey string
+
+	var buf strings.Builder
+	handler := NewContextHandler(
+		WithBaseHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})),
+		WithDynamicLevel(func(ctx context.Context) (slog.Level, bool) {
+			if verbose, _ := ctx.Value(verboseContextKey("verbose")).(bool); verbose {
+				return slog.LevelDebug, true
+			}
+			return 0, false
+		}),
+	)
+
+	logger := slog.New(handler).WithGroup("g")
+
+	logger.DebugContext(context.Background(), "suppressed")
+	assert.Equal(t, buf.String(), "")
+
+	ctx := context.WithValue(context.Background(), verboseContextKey("verbose"), true)
+	logger.DebugContext(ctx, "emitted")
+	assert.Assert(t, strings.Contains(buf.String(), "msg=emitted"))
+	assert.Assert(t, !strings.Contains(buf.String(), "suppressed"))
+}
+
+func TestContextHandlerCache(t *testing.T) {
+	type userContextKey string
+
+	calls := 0
+	th := &testHandler{}
+	handler := NewContextHandler(
+		WithBaseHandler(th),
+		WithExtractor(func(ctx context.Context) []slog.Attr {
+			calls++
+			if v, ok := ctx.Value(userContextKey("user")).(string); ok {
+				return []slog.Attr{slog.String("user", v)}
+			}
+			return nil
+		}),
+	)
+
+	logger := slog.New(handler)
+
+	ctx := context.WithValue(context.Background(), userContextKey("user"), "alice")
+	cached := ContextWithCache(ctx)
+
+	logger.InfoContext(cached, "first")
+	logger.InfoContext(cached, "second")
+	logger.With(slog.Int("n", 1)).InfoContext(cached, "third")
+	assert.Equal(t, calls, 1)
+
+	logger.InfoContext(ctx, "uncached")
+	logger.InfoContext(ctx, "uncached")
+	assert.Equal(t, calls, 3)
+
+	logger.InfoContext(ContextWithCache(ctx), "fresh cache")
+	assert.Equal(t, calls, 4)
+
+	for _, rec := range th.records {
+		var user string
+		rec.Attrs(func(attr slog.Attr) bool {
+			if attr.Key == "user" {
+				user = attr.Value.String()
+			}
+			return true
+		})
+		assert.Equal(t, user, "alice", "record %q", rec.Message)
+	}
+}
+
+func TestContextWithCacheConcurrent(t *testing.T) {
+	var calls atomic.Int32
+	entered := make(chan struct{})
+	release := make(chan struct{})
+
+	logger := slog.New(NewContextHandler(
+		WithBaseHandler(discardHandler{}),
+		WithExtractor(func(_ context.Context) []slog.Attr {
+			if calls.Add(1) == 1 {
+				close(entered)
+			}
+			<-release
+			return []slog.Attr{slog.String("user", "alice")}
+		}),
+	))
+
+	ctx := ContextWithCache(context.Background())
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			logger.InfoContext(ctx, "concurrent")
+		}()
+	}
+
+	<-entered
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, calls.Load(), int32(1))
+}
+
+type discardHandler struct{}
+
+func (discardHandler) Enabled(_ context.Context, _ slog.Level) bool { return true }
+
+func (discardHandler) Handle(_ context.Context, _ slog.Record) error { return nil }
+
+func (h discardHandler) WithAttrs(_ []slog.Attr) slog.Handler { return h }
+
+func (h discardHandler) WithGroup(_ string) slog.Handler { return h }
+
+func BenchmarkContextHandlerCached(b *testing.B) {
+	handler := NewContextHandler(
+		WithBaseHandler(discardHandler{}),
+		WithExtractor(func(_ context.Context) []slog.Attr {
+			return []slog.Attr{slog.String("user", "alice")}
+		}),
+	)
+	logger := slog.New(handler)
+	ctx := ContextWithCache(context.Background())
+
+	b.ReportAllocs()
+	for b.Loop() {
+		logger.InfoContext(ctx, "benchmark")
+	}
+}
+
+func BenchmarkContextHandlerNoMatch(b *testing.B) {
+	type userContextKey string
+
+	logger := slog.New(NewContextHandler(
+		WithBaseHandler(discardHandler{}),
+		WithExtractor(func(ctx context.Context) []slog.Attr {
+			if v := ctx.Value(userContextKey("userID")); v != nil {
+				return []slog.Attr{slog.Any("userID", v)}
+			}
+			return nil
+		}),
+	))
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		logger.InfoContext(ctx, "no match")
+	}
+}
@@ -0,0 +1,59 @@
+package ctxlog
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"slices"
+)
+
+// attrsKey is the context key under which the injected attributes are stored.
+type attrsKey struct{}
+
+// ContextWithAttrs returns a copy of ctx carrying the given attributes in
+// addition to the ones already injected in ctx, if any. They are picked up by
+// ContextAttrsExtractor, so that every subsequent log record emitted with the
+// returned context includes them.
+//
+// Example:
+//
+//	ctx = ctxlog.ContextWithAttrs(ctx, slog.String("tenantID", tenant.ID))
+func ContextWithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	if len(attrs) == 0 {
+		return ctx
+	}
+
+	existing, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+
+	// always copy, so that contexts sharing a parent never share a backing array
+	return context.WithValue(ctx, attrsKey{}, slices.Concat(existing, attrs))
+}
+
+// Inject returns an HTTP middleware that adds the given attributes to the
+// request context with ContextWithAttrs.
+//
+// Example:
+//
+//	handler := ctxlog.Inject(slog.String("service", "billing"))(mux)
+func Inject(attrs ...slog.Attr) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(ContextWithAttrs(r.Context(), attrs...)))
+		})
+	}
+}
+
+// ContextAttrsExtractor returns an AttrExtractor that emits the attributes
+// stored in the context by ContextWithAttrs and Inject.
+//
+// Example:
+//
+//	handler := ctxlog.NewContextHandler(
+//		ctxlog.WithExtractor(ctxlog.ContextAttrsExtractor()),
+//	)
+func ContextAttrsExtractor() AttrExtractor {
+	return func(ctx context.Context) []slog.Attr {
+		attrs, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+		return attrs
+	}
+}
@@ -0,0 +1,69 @@
+package ctxlog
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestInject(t *testing.T) {
+	t.Parallel()
+
+	th := &testHandler{}
+	logger := slog.New(NewContextHandler(
+		WithBaseHandler(th),
+		WithExtractor(ContextAttrsExtractor()),
+	))
+
+	tenant := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := ContextWithAttrs(r.Context(), slog.String("tenantID", "acme"))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+
+	handler := Inject(slog.String("service", "billing"))(tenant(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		logger.InfoContext(r.Context(), "downstream")
+	})))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, len(th.records), 1)
+
+	got := map[string]string{}
+	th.records[0].Attrs(func(a slog.Attr) bool {
+		got[a.Key] = a.Value.String()
+		return true
+	})
+	assert.DeepEqual(t, got, map[string]string{"service": "billing", "tenantID": "acme"})
+}
+
+func TestContextWithAttrs(t *testing.T) {
+	t.Parallel()
+
+	extract := ContextAttrsExtractor()
+
+	assert.Equal(t, len(extract(context.Background())), 0)
+
+	parent := ContextWithAttrs(context.Background(), slog.String("a", "1"))
+	assert.Equal(t, ContextWithAttrs(parent), parent)
+
+	child1 := ContextWithAttrs(parent, slog.String("b", "2"))
+	child2 := ContextWithAttrs(parent, slog.String("c", "3"))
+
+	assert.DeepEqual(t, keys(extract(parent)), []string{"a"})
+	assert.DeepEqual(t, keys(extract(child1)), []string{"a", "b"})
+	assert.DeepEqual(t, keys(extract(child2)), []string{"a", "c"})
+}
+
+func keys(attrs []slog.Attr) []string {
+	out := make([]string, len(attrs))
+	for i, a := range attrs {
+		out[i] = a.Key
+	}
+	return out
+}
@@ -7,8 +7,6 @@
 //	package main
 //
 //	import (
-//		"context"
-//		"errors"
 //		"log/slog"
 //		"net/http"
 //		"os"
@@ -17,60 +15,36 @@
 //		"github.com/paccolamano/golazy/gracely"
 //	)
 //
-//	// HTTPService wraps an http.Server to implement gracely.Service
-//	type HTTPService struct {
-//		name   string
-//		logger *slog.Logger
-//		server *http.Server
-//	}
-//
-//	// Run starts the HTTP server and blocks until context is canceled
-//	func (s *HTTPService) Run(ctx context.Context) {
-//		s.logger.Info("Starting service", "name", s.name, "addr", s.server.Addr)
-//		if err := s.server.ListenAndServe(); err != nil {
-//			if !errors.Is(err, http.ErrServerClosed) {
-//				s.logger.Error("failed to start service %q: %v", s.name, err)
-//			}
-//		}
-//	}
-//
-//	// Shutdown gracefully shuts down the HTTP server
-//	func (s *HTTPService) Shutdown(ctx context.Context) {
-//		s.logger.Info("Stopping service", "name", s.name)
-//		if err := s.server.Shutdown(ctx); err != nil {
-//			s.logger.Error("failed to stop service %q: %v", s.name, err)
-//		}
-//	}
-//
 //	func main() {
 //		logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 //
-//		apiserver := &HTTPService{
-//			name:   "apiserver",
-//			logger: logger,
-//			server: &http.Server{
-//				Addr: ":8080",
-//				Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-//					_, _ = w.Write([]byte("Hello from gracely HTTP service!\n"))
-//				}),
-//			},
-//		}
+//		apiserver := gracely.NewHTTPService("apiserver", &http.Server{
+//			Addr: ":8080",
+//			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//				_, _ = w.Write([]byte("Hello from gracely HTTP service!\n"))
+//			}),
+//		}, logger)
 //
 //		// Start the service with graceful shutdown
-//		gracely.Start([]gracely.Service{apiserver},
+//		if err := gracely.Start([]gracely.Service{apiserver},
 //			gracely.WithLogger(logger),
 //			gracely.WithTimeout(5*time.Second),
-//		)
+//		); err != nil {
+//			logger.Error("Service failed", slog.Any("err", err))
+//		}
 //
 //		logger.Info("Main function exiting")
 //	}
 package gracely
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"log/slog"
 	"os"
 	"os/signal"
+	"slices"
 	"sync"
 	"syscall"
 	"time"
@@ -83,12 +57,32 @@ type Logger interface {
 	LogAttrs(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr)
 }
 
+// Discard is a Logger that discards all log messages. It is the default
+// logger used by Start.
+var Discard Logger = noopLogger{}
+
 // noopLogger is a default logger that discards all log messages.
 type noopLogger struct{}
 
 // LogAttrs for noopLogger does nothing.
 func (noopLogger) LogAttrs(_ context.Context, _ slog.Level, _ string, _ ...slog.Attr) {}
 
+// Clock abstracts the passing of time for Start, allowing tests to trigger
+// the shutdown timeout deterministically.
+type Clock interface {
+	// After waits for the duration to elapse and then sends the current time
+	// on the returned channel, like time.After.
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock is the default Clock, backed by the time package.
+type realClock struct{}
+
+// After for realClock calls time.After.
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
 // Service represents a long-running service with graceful shutdown support.
 type Service interface {
 	// Run starts the service. It should block until the service is stopped or the context is cancelled.
@@ -99,11 +93,109 @@ type Service interface {
 	Shutdown(ctx context.Context)
 }
 
+// RunnerE can be implemented by a Service whose run can fail. Start then
+// calls RunE instead of Run, and returns the error, if any.
+type RunnerE interface {
+	// RunE starts the service like Service.Run, returning an error if it fails.
+	RunE(ctx context.Context) error
+}
+
+// ShutdownerE can be implemented by a Service whose shutdown can fail. Start
+// then calls ShutdownE instead of Shutdown, and returns the error, if any.
+type ShutdownerE interface {
+	// ShutdownE stops the service like Service.Shutdown, returning an error if it fails.
+	ShutdownE(ctx context.Context) error
+}
+
+// run runs svc, preferring RunE when implemented.
+func run(ctx context.Context, svc Service) error {
+	if r, ok := svc.(RunnerE); ok {
+		return r.RunE(ctx)
+	}
+	svc.Run(ctx)
+	return nil
+}
+
+// shutdown shuts svc down, preferring ShutdownE when implemented.
+func shutdown(ctx context.Context, svc Service) error {
+	if s, ok := svc.(ShutdownerE); ok {
+		return s.ShutdownE(ctx)
+	}
+	svc.Shutdown(ctx)
+	return nil
+}
+
+// errorList collects errors from concurrent goroutines.
+type errorList struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+// add records err, if not nil.
+func (l *errorList) add(err error) {
+	if err == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.errs = append(l.errs, err)
+}
+
+// join returns the errors recorded so far joined with errors.Join.
+func (l *errorList) join() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return errors.Join(l.errs...)
+}
+
+// Prioritized can be implemented by a Service to control the order in which
+// Start shuts the services down. Services with a higher priority are shut
+// down first, and the next priority is only shut down once all the services
+// of the previous one have returned from Shutdown. Services sharing the same
+// priority are shut down concurrently. Services that do not implement
+// Prioritized have priority 0.
+//
+// For example, an HTTP server with priority 10 stops accepting requests
+// before the database pool it depends on, left at priority 0, is closed.
+type Prioritized interface {
+	// ShutdownPriority returns the shutdown priority of the service.
+	ShutdownPriority() int
+}
+
+// shutdownPriority returns the priority of svc, or 0 if it does not
+// implement Prioritized.
+func shutdownPriority(svc Service) int {
+	if p, ok := svc.(Prioritized); ok {
+		return p.ShutdownPriority()
+	}
+	return 0
+}
+
+// shutdownGroups groups services by priority, from the highest priority to
+// the lowest. Within a group, services keep their original order.
+func shutdownGroups(services []Service) [][]Service {
+	sorted := slices.Clone(services)
+	slices.SortStableFunc(sorted, func(a, b Service) int {
+		return cmp.Compare(shutdownPriority(b), shutdownPriority(a))
+	})
+
+	var groups [][]Service
+	for i, svc := range sorted {
+		if i == 0 || shutdownPriority(svc) != shutdownPriority(sorted[i-1]) {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], svc)
+	}
+	return groups
+}
+
 // config holds the configuration for Start and is modified by Options.
 type config struct {
 	logger  Logger
 	timeout time.Duration
 	signals []os.Signal
+	clock   Clock
 }
 
 // Option defines a functional option for configuring gracely.
@@ -133,22 +225,38 @@ func WithSignals(s ...os.Signal) Option {
 	}
 }
 
+// WithClock sets the Clock used to wait for the shutdown timeout.
+// Default is a Clock backed by the time package.
+func WithClock(clock Clock) Option {
+	return func(c *config) {
+		c.clock = clock
+	}
+}
+
 // Start launches the given services concurrently and handles graceful shutdown.
 //
 // It listens for OS signals (SIGINT, SIGTERM by default), cancels the context for all
 // services when a signal is received, and calls Shutdown on each service with a
-// configurable timeout.
+// configurable timeout. Services are shut down concurrently, unless they
+// implement Prioritized: in that case they are shut down by decreasing priority.
+//
+// It returns the errors reported by the services implementing RunnerE or
+// ShutdownerE joined with errors.Join, or nil if none failed. If the timeout
+// is reached, only the errors reported until then are returned.
 //
 // Usage example:
 //
 //	services := []gracely.Service{&MyService{}}
 //	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
-//	gracely.Start(services, gracely.WithLogger(logger), gracely.WithTimeout(5*time.Second))
-func Start(services []Service, opts ...Option) {
+//	if err := gracely.Start(services, gracely.WithLogger(logger), gracely.WithTimeout(5*time.Second)); err != nil {
+//		logger.Error("services failed", slog.Any("err", err))
+//	}
+func Start(services []Service, opts ...Option) error {
 	c := &config{
-		logger:  noopLogger{},
+		logger:  Discard,
 		timeout: 10 * time.Second,
 		signals: []os.Signal{syscall.SIGINT, syscall.SIGTERM},
+		clock:   realClock{},
 	}
 
 	for _, opt := range opts {
@@ -158,28 +266,36 @@ func Start(services []Service, opts ...Option) {
 	ctx, stop := signal.NotifyContext(context.Background(), c.signals...)
 	defer stop()
 
-	var wg sync.WaitGroup
+	var (
+		wg   sync.WaitGroup
+		errs errorList
+	)
 
 	for _, svc := range services {
 		wait(&wg, func() {
-			svc.Run(ctx)
+			errs.add(run(ctx, svc))
 		})
 	}
 
 	<-ctx.Done()
 	c.logger.LogAttrs(ctx, slog.LevelInfo, "shutdown signal received", slog.Duration("timeout", c.timeout))
 
-	shutdownCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	// ctx is already cancelled by the signal: the shutdown context keeps its
+	// values but not its cancellation, so that services get the full timeout
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.timeout)
 	defer cancel()
 
-	for _, svc := range services {
-		wait(&wg, func() {
-			svc.Shutdown(shutdownCtx)
-		})
-	}
-
 	done := make(chan struct{})
 	go func() {
+		for _, group := range shutdownGroups(services) {
+			var gwg sync.WaitGroup
+			for _, svc := range group {
+				wait(&gwg, func() {
+					errs.add(shutdown(shutdownCtx, svc))
+				})
+			}
+			gwg.Wait()
+		}
 		wg.Wait()
 		close(done)
 	}()
@@ -187,9 +303,11 @@ func Start(services []Service, opts ...Option) {
 	select {
 	case <-done:
 		c.logger.LogAttrs(context.Background(), slog.LevelInfo, "graceful shutdown completed")
-	case <-time.After(c.timeout):
+	case <-c.clock.After(c.timeout):
 		c.logger.LogAttrs(context.Background(), slog.LevelWarn, "forced shutdown: timeout reached")
 	}
+
+	return errs.join()
 }
 
 func wait(wg *sync.WaitGroup, f func()) {
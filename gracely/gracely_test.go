@@ -0,0 +1,94 @@
+package gracely
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/paccolamano/golazy/utility"
+	"gotest.tools/v3/assert"
+)
+
+func TestDiscard(t *testing.T) {
+	t.Parallel()
+
+	var l Logger = Discard
+	l.LogAttrs(context.Background(), slog.LevelError, "discarded", slog.String("foo", "bar"))
+
+	c := &config{}
+	WithLogger(Discard)(c)
+	assert.Equal(t, c.logger, Discard)
+}
+
+type prioritizedService struct {
+	name     string
+	priority int
+}
+
+func (s *prioritizedService) Run(_ context.Context) {}
+
+func (s *prioritizedService) Shutdown(_ context.Context) {}
+
+func (s *prioritizedService) ShutdownPriority() int {
+	return s.priority
+}
+
+type plainService struct {
+	name string
+}
+
+func (s *plainService) Run(_ context.Context) {}
+
+func (s *plainService) Shutdown(_ context.Context) {}
+
+func TestShutdownGroups(t *testing.T) {
+	t.Parallel()
+
+	name := func(svc Service) string {
+		switch s := svc.(type) {
+		case *prioritizedService:
+			return s.name
+		case *plainService:
+			return s.name
+		}
+		return ""
+	}
+
+	tests := []struct {
+		name     string
+		services []Service
+		expected [][]string
+	}{
+		{
+			name:     "without services",
+			services: nil,
+			expected: nil,
+		},
+		{
+			name:     "without priorities",
+			services: []Service{&plainService{name: "a"}, &plainService{name: "b"}},
+			expected: [][]string{{"a", "b"}},
+		},
+		{
+			name: "with priorities",
+			services: []Service{
+				&prioritizedService{name: "db", priority: -10},
+				&plainService{name: "cache"},
+				&prioritizedService{name: "http", priority: 10},
+				&prioritizedService{name: "worker", priority: 0},
+				&prioritizedService{name: "grpc", priority: 10},
+			},
+			expected: [][]string{{"http", "grpc"}, {"cache", "worker"}, {"db"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res [][]string
+			for _, group := range shutdownGroups(tt.services) {
+				res = append(res, utility.Map(group, name))
+			}
+			assert.DeepEqual(t, res, tt.expected)
+		})
+	}
+}
@@ -0,0 +1,278 @@
+//go:build unix
+
+package gracely
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"slices"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+type mockLogger struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (m *mockLogger) LogAttrs(_ context.Context, _ slog.Level, msg string, _ ...slog.Attr) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, msg)
+}
+
+func (m *mockLogger) Messages() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.messages...)
+}
+
+// fakeClock is a Clock whose timers fire only when the test says so.
+type fakeClock struct {
+	ch chan time.Time
+}
+
+func (c *fakeClock) After(time.Duration) <-chan time.Time {
+	return c.ch
+}
+
+// mockService stops the process with SIGUSR1 as soon as it runs and blocks
+// in Shutdown until release is closed.
+type mockService struct {
+	release chan struct{}
+}
+
+func (s *mockService) Run(ctx context.Context) {
+	_ = syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+	<-ctx.Done()
+}
+
+func (s *mockService) Shutdown(_ context.Context) {
+	<-s.release
+}
+
+// recordingService appends its name to a shared slice when shut down.
+type recordingService struct {
+	name     string
+	priority int
+	mu       *sync.Mutex
+	record   *[]string
+}
+
+func (s *recordingService) Run(ctx context.Context) {
+	<-ctx.Done()
+}
+
+func (s *recordingService) Shutdown(_ context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	*s.record = append(*s.record, s.name)
+}
+
+func (s *recordingService) ShutdownPriority() int {
+	return s.priority
+}
+
+// failingService fails with runErr in RunE and shutdownErr in ShutdownE.
+type failingService struct {
+	runErr      error
+	shutdownErr error
+}
+
+func (s *failingService) Run(_ context.Context) {}
+
+func (s *failingService) RunE(_ context.Context) error {
+	return s.runErr
+}
+
+func (s *failingService) Shutdown(_ context.Context) {}
+
+func (s *failingService) ShutdownE(_ context.Context) error {
+	return s.shutdownErr
+}
+
+func TestStart(t *testing.T) {
+	t.Run("should complete the graceful shutdown", func(t *testing.T) {
+		logger := &mockLogger{}
+		svc := &mockService{release: make(chan struct{})}
+		close(svc.release)
+
+		err := Start([]Service{svc},
+			WithLogger(logger),
+			WithSignals(syscall.SIGUSR1),
+			WithClock(&fakeClock{ch: make(chan time.Time)}),
+		)
+		assert.NilError(t, err)
+
+		assert.DeepEqual(t, logger.Messages(), []string{
+			"shutdown signal received",
+			"graceful shutdown completed",
+		})
+	})
+
+	t.Run("should force the shutdown when the timeout is reached", func(t *testing.T) {
+		logger := &mockLogger{}
+		svc := &mockService{release: make(chan struct{})}
+		defer close(svc.release)
+
+		clock := &fakeClock{ch: make(chan time.Time, 1)}
+		clock.ch <- time.Now()
+
+		Start([]Service{svc},
+			WithLogger(logger),
+			WithSignals(syscall.SIGUSR1),
+			WithTimeout(time.Hour),
+			WithClock(clock),
+		)
+
+		assert.DeepEqual(t, logger.Messages(), []string{
+			"shutdown signal received",
+			"forced shutdown: timeout reached",
+		})
+	})
+
+	t.Run("should shut down services by decreasing priority", func(t *testing.T) {
+		var (
+			mu     sync.Mutex
+			record []string
+		)
+		newService := func(name string, priority int) *recordingService {
+			return &recordingService{name: name, priority: priority, mu: &mu, record: &record}
+		}
+
+		trigger := &mockService{release: make(chan struct{})}
+		close(trigger.release)
+
+		err := Start([]Service{
+			newService("db", -10),
+			trigger,
+			newService("http", 10),
+			newService("worker", 0),
+			newService("grpc", 10),
+		},
+			WithSignals(syscall.SIGUSR1),
+			WithClock(&fakeClock{ch: make(chan time.Time)}),
+		)
+
+		assert.NilError(t, err)
+		assert.Equal(t, len(record), 4)
+		assert.DeepEqual(t, slices.Sorted(slices.Values(record[:2])), []string{"grpc", "http"})
+		assert.DeepEqual(t, record[2:], []string{"worker", "db"})
+	})
+
+	t.Run("should return the joined errors of all the services", func(t *testing.T) {
+		errRunA := errors.New("a: run failed")
+		errShutdownA := errors.New("a: shutdown failed")
+		errShutdownB := errors.New("b: shutdown failed")
+
+		trigger := &mockService{release: make(chan struct{})}
+		close(trigger.release)
+
+		err := Start([]Service{
+			&failingService{runErr: errRunA, shutdownErr: errShutdownA},
+			&failingService{shutdownErr: errShutdownB},
+			&failingService{},
+			trigger,
+		},
+			WithSignals(syscall.SIGUSR1),
+			WithClock(&fakeClock{ch: make(chan time.Time)}),
+		)
+
+		assert.ErrorIs(t, err, errRunA)
+		assert.ErrorIs(t, err, errShutdownA)
+		assert.ErrorIs(t, err, errShutdownB)
+		assert.Equal(t, len(err.(interface{ Unwrap() []error }).Unwrap()), 3)
+	})
+
+	t.Run("should return the error of an http service failing to start", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NilError(t, err)
+		t.Cleanup(func() { _ = ln.Close() })
+
+		trigger := &mockService{release: make(chan struct{})}
+		close(trigger.release)
+
+		svc := NewHTTPService("api", &http.Server{Addr: ln.Addr().String(), ReadHeaderTimeout: time.Second}, nil)
+		err = Start([]Service{svc, trigger},
+			WithSignals(syscall.SIGUSR1),
+			WithClock(&fakeClock{ch: make(chan time.Time)}),
+		)
+		assert.ErrorContains(t, err, "address already in use")
+	})
+
+	t.Run("should drain the in-flight requests of an http service", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NilError(t, err)
+		addr := ln.Addr().String()
+		assert.NilError(t, ln.Close())
+
+		entered := make(chan struct{})
+		release := make(chan struct{})
+		server := &http.Server{
+			Addr: addr,
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/slow" {
+					close(entered)
+					<-release
+				}
+				w.WriteHeader(http.StatusOK)
+			}),
+			ReadHeaderTimeout: time.Second,
+		}
+
+		logger := &attrLogger{entries: map[string][]slog.Attr{}}
+		svc := NewHTTPService("api", server, logger)
+
+		startErr := make(chan error, 1)
+		go func() {
+			startErr <- Start([]Service{svc}, WithSignals(syscall.SIGUSR1), WithTimeout(time.Hour))
+		}()
+
+		assert.Assert(t, waitFor(func() bool {
+			resp, err := http.Get("http://" + addr + "/ready")
+			if err != nil {
+				return false
+			}
+			_ = resp.Body.Close()
+			return true
+		}))
+
+		respStatus := make(chan int, 1)
+		go func() {
+			resp, err := http.Get("http://" + addr + "/slow")
+			if err != nil {
+				respStatus <- 0
+				return
+			}
+			_ = resp.Body.Close()
+			respStatus <- resp.StatusCode
+		}()
+
+		<-entered
+		assert.NilError(t, syscall.Kill(syscall.Getpid(), syscall.SIGUSR1))
+		assert.Assert(t, waitFor(func() bool {
+			_, ok := logger.get("waiting for in-flight requests")
+			return ok
+		}))
+
+		select {
+		case err := <-startErr:
+			t.Fatalf("Start returned before the in-flight request completed: %v", err)
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		close(release)
+		assert.Equal(t, <-respStatus, http.StatusOK)
+		assert.NilError(t, <-startErr)
+
+		_, failed := logger.get("failed to stop service")
+		assert.Assert(t, !failed)
+	})
+}
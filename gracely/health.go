@@ -0,0 +1,99 @@
+package gracely
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+)
+
+// HealthChecker checks the health of a dependency, such as a database,
+// a cache or a message queue.
+type HealthChecker interface {
+	// Check returns a non-nil error if the dependency is unhealthy.
+	Check(ctx context.Context) error
+}
+
+// HealthCheckerFunc is an adapter to allow the use of ordinary functions
+// as HealthChecker.
+type HealthCheckerFunc func(ctx context.Context) error
+
+// Check calls f(ctx).
+func (f HealthCheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+const (
+	healthStatusOK        = "ok"
+	healthStatusUnhealthy = "unhealthy"
+)
+
+// healthReport is the JSON body written by HealthHandler.
+type healthReport struct {
+	Status string                 `json:"status"`
+	Checks map[string]checkResult `json:"checks"`
+}
+
+// checkResult is the outcome of a single HealthChecker.
+type checkResult struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// HealthHandler returns an http.Handler, typically mounted on /healthz, that
+// runs all the given checks concurrently with the request context. It responds
+// with 200 when all of them pass, and with 503 otherwise. The JSON body
+// reports the overall status and the status of each check by name, along with
+// the error message of the failing ones:
+//
+//	{"status":"unhealthy","checks":{"cache":{"status":"ok"},"db":{"status":"unhealthy","error":"connection refused"}}}
+//
+// Example:
+//
+//	mux.Handle("/healthz", gracely.HealthHandler(map[string]gracely.HealthChecker{
+//		"db": gracely.HealthCheckerFunc(db.PingContext),
+//	}))
+func HealthHandler(checks map[string]HealthChecker) http.Handler {
+	checks = maps.Clone(checks)
+	names := slices.Sorted(maps.Keys(checks))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		results := make([]checkResult, len(names))
+
+		var wg sync.WaitGroup
+		for i, name := range names {
+			wait(&wg, func() {
+				results[i] = runCheck(r.Context(), checks[name])
+			})
+		}
+		wg.Wait()
+
+		report := healthReport{Status: healthStatusOK, Checks: make(map[string]checkResult, len(names))}
+		for i, name := range names {
+			report.Checks[name] = results[i]
+			if results[i].Status != healthStatusOK {
+				report.Status = healthStatusUnhealthy
+			}
+		}
+
+		status := http.StatusOK
+		if report.Status != healthStatusOK {
+			status = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(report)
+	})
+}
+
+// runCheck runs c, reporting it as unhealthy if it fails.
+func runCheck(ctx context.Context, c HealthChecker) checkResult {
+	if err := c.Check(ctx); err != nil {
+		return checkResult{Status: healthStatusUnhealthy, Error: err.Error()}
+	}
+	return checkResult{Status: healthStatusOK}
+}
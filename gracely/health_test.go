@@ -0,0 +1,72 @@
+package gracely
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestHealthHandler(t *testing.T) {
+	t.Parallel()
+
+	healthy := HealthCheckerFunc(func(_ context.Context) error { return nil })
+	unhealthy := HealthCheckerFunc(func(_ context.Context) error { return errors.New("connection refused") })
+
+	tests := []struct {
+		name           string
+		checks         map[string]HealthChecker
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "without checks",
+			checks:         nil,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"ok","checks":{}}` + "\n",
+		},
+		{
+			name:           "with all checks passing",
+			checks:         map[string]HealthChecker{"db": healthy, "cache": healthy},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"ok","checks":{"cache":{"status":"ok"},"db":{"status":"ok"}}}` + "\n",
+		},
+		{
+			name:           "with an unhealthy dependency",
+			checks:         map[string]HealthChecker{"db": unhealthy, "cache": healthy, "queue": healthy},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody: `{"status":"unhealthy","checks":{"cache":{"status":"ok"},` +
+				`"db":{"status":"unhealthy","error":"connection refused"},"queue":{"status":"ok"}}}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			HealthHandler(tt.checks).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+			assert.Equal(t, w.Code, tt.expectedStatus)
+			assert.Equal(t, w.Header().Get("Content-Type"), "application/json; charset=utf-8")
+			assert.Equal(t, w.Body.String(), tt.expectedBody)
+		})
+	}
+
+	t.Run("should pass the request context to the checks", func(t *testing.T) {
+		type ctxKey struct{}
+
+		var got any
+		h := HealthHandler(map[string]HealthChecker{
+			"db": HealthCheckerFunc(func(ctx context.Context) error {
+				got = ctx.Value(ctxKey{})
+				return nil
+			}),
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+		h.ServeHTTP(httptest.NewRecorder(), req.WithContext(context.WithValue(req.Context(), ctxKey{}, "value")))
+		assert.Equal(t, got, "value")
+	})
+}
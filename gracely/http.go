@@ -0,0 +1,118 @@
+package gracely
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+)
+
+// HTTPService wraps an http.Server to implement Service. It keeps track of
+// the connections currently serving a request, so that the drain progress
+// can be observed during shutdown.
+type HTTPService struct {
+	name   string
+	server *http.Server
+	logger Logger
+
+	mu    sync.Mutex
+	conns map[net.Conn]http.ConnState
+}
+
+// NewHTTPService creates a new HTTPService for the given server. It installs
+// an http.Server.ConnState hook on the server, chaining the existing one if any.
+// A nil logger discards all messages.
+func NewHTTPService(name string, server *http.Server, logger Logger) *HTTPService {
+	if logger == nil {
+		logger = Discard
+	}
+
+	s := &HTTPService{
+		name:   name,
+		server: server,
+		logger: logger,
+		conns:  make(map[net.Conn]http.ConnState),
+	}
+
+	next := server.ConnState
+	server.ConnState = func(conn net.Conn, state http.ConnState) {
+		s.trackConn(conn, state)
+		if next != nil {
+			next(conn, state)
+		}
+	}
+
+	return s
+}
+
+// InFlight returns the number of connections currently serving a request.
+func (s *HTTPService) InFlight() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := 0
+	for _, state := range s.conns {
+		if state == http.StateActive {
+			n++
+		}
+	}
+	return n
+}
+
+// Run starts the HTTP server and blocks until it is shut down, logging the
+// error if it fails to start.
+func (s *HTTPService) Run(ctx context.Context) {
+	if err := s.RunE(ctx); err != nil {
+		s.logger.LogAttrs(ctx, slog.LevelError, "failed to start service",
+			slog.String("name", s.name), slog.String("err", err.Error()))
+	}
+}
+
+// RunE starts the HTTP server and blocks until it is shut down. It returns
+// the error if the server fails, ignoring http.ErrServerClosed.
+func (s *HTTPService) RunE(ctx context.Context) error {
+	s.logger.LogAttrs(ctx, slog.LevelInfo, "starting service",
+		slog.String("name", s.name), slog.String("addr", s.server.Addr))
+
+	if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown gracefully shuts down the HTTP server, waiting for the in-flight
+// requests to complete or ctx to be done, and logs the error if any.
+func (s *HTTPService) Shutdown(ctx context.Context) {
+	if err := s.ShutdownE(ctx); err != nil {
+		s.logger.LogAttrs(ctx, slog.LevelError, "failed to stop service",
+			slog.String("name", s.name), slog.Int("inFlight", s.InFlight()), slog.String("err", err.Error()))
+	}
+}
+
+// ShutdownE gracefully shuts down the HTTP server like Shutdown, returning
+// the error instead of logging it.
+func (s *HTTPService) ShutdownE(ctx context.Context) error {
+	s.logger.LogAttrs(ctx, slog.LevelInfo, "stopping service", slog.String("name", s.name))
+
+	if n := s.InFlight(); n > 0 {
+		s.logger.LogAttrs(ctx, slog.LevelInfo, "waiting for in-flight requests",
+			slog.String("name", s.name), slog.Int("inFlight", n))
+	}
+
+	return s.server.Shutdown(ctx)
+}
+
+// trackConn records the state of conn, forgetting closed connections.
+func (s *HTTPService) trackConn(conn net.Conn, state http.ConnState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch state {
+	case http.StateClosed, http.StateHijacked:
+		delete(s.conns, conn)
+	default:
+		s.conns[conn] = state
+	}
+}
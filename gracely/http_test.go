@@ -0,0 +1,105 @@
+package gracely
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+type attrLogger struct {
+	mu      sync.Mutex
+	entries map[string][]slog.Attr
+}
+
+func (l *attrLogger) LogAttrs(_ context.Context, _ slog.Level, msg string, attrs ...slog.Attr) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries[msg] = attrs
+}
+
+func (l *attrLogger) get(msg string) ([]slog.Attr, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	attrs, ok := l.entries[msg]
+	return attrs, ok
+}
+
+func TestHTTPServiceInFlight(t *testing.T) {
+	t.Parallel()
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+
+	var hookCalls int
+	var hookMu sync.Mutex
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			close(entered)
+			<-release
+			w.WriteHeader(http.StatusOK)
+		}),
+		ConnState: func(net.Conn, http.ConnState) {
+			hookMu.Lock()
+			hookCalls++
+			hookMu.Unlock()
+		},
+		ReadHeaderTimeout: time.Second,
+	}
+
+	logger := &attrLogger{entries: map[string][]slog.Attr{}}
+	svc := NewHTTPService("api", server, logger)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	go func() { _ = server.Serve(ln) }()
+
+	respErr := make(chan error, 1)
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String())
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		respErr <- err
+	}()
+
+	<-entered
+	assert.Equal(t, svc.InFlight(), 1)
+
+	shutdownDone := make(chan struct{})
+	go func() {
+		svc.Shutdown(context.Background())
+		close(shutdownDone)
+	}()
+
+	assert.Assert(t, waitFor(func() bool {
+		_, ok := logger.get("waiting for in-flight requests")
+		return ok
+	}))
+	attrs, _ := logger.get("waiting for in-flight requests")
+	assert.Equal(t, attrs[1].Value.Int64(), int64(1))
+
+	close(release)
+	<-shutdownDone
+	assert.NilError(t, <-respErr)
+	assert.Equal(t, svc.InFlight(), 0)
+
+	hookMu.Lock()
+	defer hookMu.Unlock()
+	assert.Assert(t, hookCalls > 0, "existing ConnState hook should be chained")
+}
+
+func waitFor(cond func() bool) bool {
+	for range 100 {
+		if cond() {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
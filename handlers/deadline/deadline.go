@@ -0,0 +1,145 @@
+// Package deadline provides an HTTP middleware that lets clients request a
+// deadline for their request through a header (default "X-Request-Timeout"),
+// capped by a server-side maximum.
+//
+// The requested timeout is applied to the request context with
+// context.WithTimeout, so that handlers and downstream calls honoring the
+// context give up once it expires.
+//
+// Example usage:
+//
+//	package main
+//
+//	import (
+//		"log"
+//		"net/http"
+//		"time"
+//
+//		"github.com/paccolamano/golazy/handlers/deadline"
+//	)
+//
+//	func main() {
+//		mux := http.NewServeMux()
+//		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+//			if d, ok := deadline.GetDeadline(r); ok {
+//				log.Printf("request must complete by %s", d)
+//			}
+//			w.Write([]byte("hello"))
+//		})
+//
+//		handler := deadline.New(
+//			deadline.WithMaxTimeout(5*time.Second),
+//			deadline.WithDefaultTimeout(2*time.Second),
+//		)(mux)
+//
+//		log.Fatal(http.ListenAndServe(":8080", handler))
+//	}
+package deadline
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+type contextKey string
+
+// deadlineKey is the context key under which the effective deadline is stored.
+const deadlineKey = contextKey("deadline")
+
+// config holds configuration options for the deadline handler.
+type config struct {
+	headerKey      string
+	maxTimeout     time.Duration
+	defaultTimeout time.Duration
+}
+
+// Option represents a functional option for configuring the deadline handler.
+type Option func(*config)
+
+// WithHeaderKey sets the request header holding the requested timeout.
+// Default is "X-Request-Timeout".
+func WithHeaderKey(key string) Option {
+	return func(c *config) {
+		c.headerKey = key
+	}
+}
+
+// WithMaxTimeout sets the upper bound applied to the requested timeout.
+// Default is 30s.
+func WithMaxTimeout(d time.Duration) Option {
+	return func(c *config) {
+		c.maxTimeout = d
+	}
+}
+
+// WithDefaultTimeout sets the timeout applied when the header is missing or
+// invalid. It is capped by the max timeout too. Default is 0, meaning that no
+// deadline is applied to such requests.
+func WithDefaultTimeout(d time.Duration) Option {
+	return func(c *config) {
+		c.defaultTimeout = d
+	}
+}
+
+// New returns a handler that reads the requested timeout from the configured
+// header, expressed as a Go duration (e.g. "2s", "500ms"), clamps it to the max
+// timeout and applies it to the request context. The effective deadline can be
+// retrieved with GetDeadline.
+//
+// Missing, invalid or non-positive values fall back to the default timeout.
+func New(opts ...Option) func(http.Handler) http.Handler {
+	c := &config{
+		headerKey:  "X-Request-Timeout",
+		maxTimeout: 30 * time.Second,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			timeout := effectiveTimeout(r.Header.Get(c.headerKey), c)
+			if timeout <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+
+			d, _ := ctx.Deadline()
+			ctx = context.WithValue(ctx, deadlineKey, d)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// GetDeadline retrieves the effective deadline stored in the request context
+// by New. The second value is false if no deadline was applied.
+func GetDeadline(r *http.Request) (time.Time, bool) {
+	if r == nil {
+		return time.Time{}, false
+	}
+
+	d, ok := r.Context().Value(deadlineKey).(time.Time)
+	return d, ok
+}
+
+// effectiveTimeout returns the timeout to apply for the given header value.
+func effectiveTimeout(header string, c *config) time.Duration {
+	timeout := c.defaultTimeout
+	if header != "" {
+		if d, err := time.ParseDuration(header); err == nil && d > 0 {
+			timeout = d
+		}
+	}
+
+	if c.maxTimeout > 0 && timeout > c.maxTimeout {
+		return c.maxTimeout
+	}
+
+	return timeout
+}
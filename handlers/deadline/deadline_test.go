@@ -0,0 +1,127 @@
+package deadline
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestWithHeaderKey(t *testing.T) {
+	t.Parallel()
+
+	opts := config{}
+	f := WithHeaderKey("X-Timeout")
+	f(&opts)
+
+	assert.Equal(t, opts.headerKey, "X-Timeout")
+}
+
+func TestWithMaxTimeout(t *testing.T) {
+	t.Parallel()
+
+	opts := config{}
+	f := WithMaxTimeout(time.Second)
+	f(&opts)
+
+	assert.Equal(t, opts.maxTimeout, time.Second)
+}
+
+func TestWithDefaultTimeout(t *testing.T) {
+	t.Parallel()
+
+	opts := config{}
+	f := WithDefaultTimeout(time.Second)
+	f(&opts)
+
+	assert.Equal(t, opts.defaultTimeout, time.Second)
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		header           string
+		opts             []Option
+		expectedDeadline bool
+		expectedTimeout  time.Duration
+	}{
+		{
+			name:             "with requested timeout",
+			header:           "2s",
+			expectedDeadline: true,
+			expectedTimeout:  2 * time.Second,
+		},
+		{
+			name:             "with oversized requested timeout",
+			header:           "1h",
+			opts:             []Option{WithMaxTimeout(5 * time.Second)},
+			expectedDeadline: true,
+			expectedTimeout:  5 * time.Second,
+		},
+		{
+			name:             "without header",
+			expectedDeadline: false,
+		},
+		{
+			name:             "with invalid header and default timeout",
+			header:           "soon",
+			opts:             []Option{WithDefaultTimeout(time.Second)},
+			expectedDeadline: true,
+			expectedTimeout:  time.Second,
+		},
+		{
+			name:             "with custom header key",
+			header:           "3s",
+			opts:             []Option{WithHeaderKey("X-Other")},
+			expectedDeadline: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var deadline time.Time
+			var ok, ctxOK bool
+			var ctxDeadline time.Time
+
+			handler := New(tt.opts...)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				deadline, ok = GetDeadline(r)
+				ctxDeadline, ctxOK = r.Context().Deadline()
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set("X-Request-Timeout", tt.header)
+			}
+
+			start := time.Now()
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, ok, tt.expectedDeadline)
+			assert.Equal(t, ctxOK, tt.expectedDeadline)
+			if !tt.expectedDeadline {
+				return
+			}
+
+			assert.Equal(t, deadline, ctxDeadline)
+			remaining := deadline.Sub(start)
+			assert.Assert(t, remaining <= tt.expectedTimeout+time.Second && remaining >= tt.expectedTimeout-time.Second,
+				"unexpected timeout %s", remaining)
+		})
+	}
+}
+
+func TestGetDeadline(t *testing.T) {
+	t.Parallel()
+
+	_, ok := GetDeadline(nil)
+	assert.Assert(t, !ok)
+
+	_, ok = GetDeadline(httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Assert(t, !ok)
+}
@@ -0,0 +1,79 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+)
+
+// Entry is a log record recorded by Capture.
+type Entry struct {
+	Level   slog.Level
+	Message string
+	Attrs   []slog.Attr
+}
+
+// Attr returns the attribute with the given key and whether it was found.
+func (e Entry) Attr(key string) (slog.Attr, bool) {
+	for _, a := range e.Attrs {
+		if a.Key == key {
+			return a, true
+		}
+	}
+	return slog.Attr{}, false
+}
+
+// Capture is a Logger that records every entry in memory. It is meant to be
+// used in tests of handlers wrapped with New. It is safe for concurrent use.
+//
+// Example:
+//
+//	capture := &logger.Capture{}
+//	handler := logger.New(logger.WithLogger(capture))(next)
+//	handler.ServeHTTP(rec, req)
+//
+//	if !capture.Has("status") {
+//		t.Error("expected status to be logged")
+//	}
+type Capture struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// LogAttrs implements Logger.
+func (c *Capture) LogAttrs(_ context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = append(c.entries, Entry{Level: level, Message: msg, Attrs: slices.Clone(attrs)})
+}
+
+// Entries returns a copy of the recorded entries, in logging order.
+func (c *Capture) Entries() []Entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return slices.Clone(c.entries)
+}
+
+// Has reports whether any recorded entry has an attribute with the given key.
+func (c *Capture) Has(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, e := range c.entries {
+		if _, ok := e.Attr(key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Reset discards all the recorded entries.
+func (c *Capture) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = nil
+}
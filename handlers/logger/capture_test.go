@@ -0,0 +1,60 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestCapture(t *testing.T) {
+	capture := &Capture{}
+	capture.LogAttrs(context.Background(), slog.LevelWarn, "first", slog.String("foo", "bar"))
+	capture.LogAttrs(context.Background(), slog.LevelError, "second")
+
+	entries := capture.Entries()
+	assert.Equal(t, len(entries), 2)
+	assert.Equal(t, entries[0].Level, slog.LevelWarn)
+	assert.Equal(t, entries[0].Message, "first")
+	assert.Equal(t, entries[1].Level, slog.LevelError)
+	assert.Equal(t, entries[1].Message, "second")
+
+	attr, ok := entries[0].Attr("foo")
+	assert.Assert(t, ok)
+	assert.Equal(t, attr.Value.String(), "bar")
+
+	_, ok = entries[1].Attr("foo")
+	assert.Assert(t, !ok)
+
+	assert.Assert(t, capture.Has("foo"))
+	assert.Assert(t, !capture.Has("missing"))
+
+	capture.Reset()
+	assert.Equal(t, len(capture.Entries()), 0)
+}
+
+func TestCaptureWithHandler(t *testing.T) {
+	capture := &Capture{}
+	handler := New(
+		WithLogger(capture),
+		WithFieldsIn(FieldMethod),
+		WithFieldsOut(FieldStatus),
+	)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	entries := capture.Entries()
+	assert.Equal(t, len(entries), 2)
+	assert.Equal(t, entries[0].Message, "incoming request")
+	assert.Equal(t, entries[1].Message, "request completed")
+
+	status, ok := entries[1].Attr("status")
+	assert.Assert(t, ok)
+	assert.Equal(t, status.Value.Int64(), int64(http.StatusTeapot))
+	assert.Assert(t, capture.Has("method"))
+}
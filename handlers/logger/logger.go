@@ -37,11 +37,18 @@ package logger
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"maps"
 	"net"
 	"net/http"
+	"net/url"
+	"slices"
 	"strings"
 	"time"
+	"unicode/utf8"
+
+	"github.com/paccolamano/golazy/utility/httputil"
 )
 
 // Logger defines the minimal logging interface required by this handler.
@@ -50,6 +57,25 @@ type Logger interface {
 	LogAttrs(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr)
 }
 
+// Discard is a Logger that discards all log messages. It is useful to
+// silence the logging handler, e.g. in tests.
+var Discard Logger = noopLogger{}
+
+// noopLogger is a Logger that does nothing.
+type noopLogger struct{}
+
+// LogAttrs for noopLogger does nothing.
+func (noopLogger) LogAttrs(_ context.Context, _ slog.Level, _ string, _ ...slog.Attr) {}
+
+// MetricsRecorder receives a sample for each request served by the logging
+// handler, to feed counters and histograms such as the Prometheus ones,
+// without this package depending on any metrics library.
+type MetricsRecorder interface {
+	// ObserveRequest records a request served with the given method, route
+	// and response status code, in duration d.
+	ObserveRequest(method, route string, status int, d time.Duration)
+}
+
 // Field represents a request/response attribute that can be logged.
 type Field string
 
@@ -60,7 +86,11 @@ const (
 	FieldPath Field = "path"
 	// FieldQuery logs the raw query string from the URL.
 	FieldQuery Field = "query"
-	// FieldIP logs the client IP address (from X-Real-IP or RemoteAddr).
+	// FieldQueryParams logs the query parameters as a group with one attribute
+	// per key: single values are logged as strings and repeated values
+	// (e.g. "?tag=a&tag=b") as an array of strings.
+	FieldQueryParams Field = "queryParams"
+	// FieldIP logs the client IP address, as returned by httputil.ClientIP.
 	FieldIP Field = "ip"
 	// FieldUserAgent logs the User-Agent header.
 	FieldUserAgent Field = "userAgent"
@@ -72,6 +102,19 @@ const (
 	FieldDuration Field = "duration"
 )
 
+// knownFields is the set of fields supported by AttrFor.
+var knownFields = map[Field]struct{}{
+	FieldMethod:        {},
+	FieldPath:          {},
+	FieldQuery:         {},
+	FieldQueryParams:   {},
+	FieldIP:            {},
+	FieldUserAgent:     {},
+	FieldContentLength: {},
+	FieldStatus:        {},
+	FieldDuration:      {},
+}
+
 // config defines configuration for the logging handler.
 type config struct {
 	// Logger to use for structured logging. Defaults to slog.Default().
@@ -80,6 +123,8 @@ type config struct {
 	LevelRequestIn slog.Level
 	// LevelRequestOut defines the log level for completed requests.
 	LevelRequestOut slog.Level
+	// LogIncoming defines whether the incoming request is logged.
+	LogIncoming bool
 	// FieldsIn is the list of request attributes to log at request start.
 	FieldsIn []Field
 	// FieldsOut is the list of response attributes to log after request completion.
@@ -88,6 +133,19 @@ type config struct {
 	SkipPaths []string
 	// SkipFunc is an optional function to skip logging for certain requests.
 	SkipFunc func(r *http.Request) bool
+	// Clock returns the current time, used to measure durations. Defaults to time.Now.
+	Clock func() time.Time
+	// TrustedProxies lists the networks whose forwarding headers are honored
+	// when resolving the client IP.
+	TrustedProxies []*net.IPNet
+	// ContextAttrs lists functions extracting attributes from the request
+	// context, added to the completion log.
+	ContextAttrs []func(ctx context.Context) []slog.Attr
+	// Metrics, when set, is notified of every request after its completion.
+	Metrics MetricsRecorder
+	// MaxValueLength, when positive, is the maximum length in characters of
+	// the logged string values. Defaults to 0, meaning no limit.
+	MaxValueLength int
 }
 
 // Option represents a functional option for configuring logger handler.
@@ -114,6 +172,15 @@ func WithRequestOutLevel(level slog.Level) Option {
 	}
 }
 
+// WithLogIncoming sets whether the "incoming request" entry is logged when a
+// request is received. Disabling it halves the log volume while keeping the
+// "request completed" entry, which is always logged. Default is true.
+func WithLogIncoming(enabled bool) Option {
+	return func(c *config) {
+		c.LogIncoming = enabled
+	}
+}
+
 // WithFieldsIn specifies which request fields to log at request start.
 func WithFieldsIn(fields ...Field) Option {
 	return func(c *config) {
@@ -142,6 +209,59 @@ func WithSkipFunc(fn func(r *http.Request) bool) Option {
 	}
 }
 
+// WithClock sets the function used to read the current time when measuring
+// request durations. It is mainly useful to get deterministic durations in tests.
+// Default is time.Now.
+func WithClock(clock func() time.Time) Option {
+	return func(c *config) {
+		c.Clock = clock
+	}
+}
+
+// WithTrustedProxies sets the networks of the reverse proxies in front of the
+// server. When the direct peer belongs to one of them, the client IP is read
+// from the X-Forwarded-For header. See httputil.ClientIP for details.
+func WithTrustedProxies(proxies ...*net.IPNet) Option {
+	return func(c *config) {
+		c.TrustedProxies = append(c.TrustedProxies, proxies...)
+	}
+}
+
+// WithContextAttrs adds functions extracting attributes from the request
+// context (e.g. the user or tenant set by an authentication middleware), which
+// are added to the completion log after the configured fields. They have the
+// same signature as ctxlog.AttrExtractor, so extractors can be shared.
+// The context is the one of the request received by the logging handler:
+// values added downstream with r.WithContext are not visible, so place the
+// middlewares storing them before the logging handler.
+func WithContextAttrs(extractors ...func(ctx context.Context) []slog.Attr) Option {
+	return func(c *config) {
+		c.ContextAttrs = append(c.ContextAttrs, extractors...)
+	}
+}
+
+// WithMaxValueLength bounds the length of the logged string values, such as
+// enormous User-Agent headers or query strings, to n characters: longer values
+// are cut to n-1 characters followed by an ellipsis ("…"). It applies to every
+// attribute of both log entries, including groups and string arrays.
+// Zero or negative values mean no limit, which is the default.
+func WithMaxValueLength(n int) Option {
+	return func(c *config) {
+		c.MaxValueLength = n
+	}
+}
+
+// WithMetrics sets a MetricsRecorder notified after each request completes.
+// The route is the pattern of the http.ServeMux route that served the request
+// (e.g. "GET /users/{id}"), which keeps the label cardinality bounded; it is
+// empty when the request did not reach a ServeMux wrapped by the logging
+// handler, or matched no route. Skipped requests are not recorded.
+func WithMetrics(recorder MetricsRecorder) Option {
+	return func(c *config) {
+		c.Metrics = recorder
+	}
+}
+
 // responseWriter is a wrapper around http.ResponseWriter
 // that captures the HTTP status code written.
 type responseWriter struct {
@@ -156,6 +276,8 @@ func (rw *responseWriter) WriteHeader(code int) {
 
 // New creates a new logging handler with the given options.
 // It returns a function that wraps an http.Handler and logs request/response details.
+// It panics if FieldsIn or FieldsOut contain an unknown Field, so that typos
+// surface at startup instead of being silently ignored.
 //
 // Example:
 //
@@ -175,6 +297,7 @@ func New(opts ...Option) func(http.Handler) http.Handler {
 		Logger:          slog.Default(),
 		LevelRequestIn:  slog.LevelInfo,
 		LevelRequestOut: slog.LevelInfo,
+		LogIncoming:     true,
 		FieldsIn: []Field{
 			FieldMethod, FieldPath, FieldQuery, FieldIP, FieldUserAgent, FieldContentLength,
 		},
@@ -183,12 +306,17 @@ func New(opts ...Option) func(http.Handler) http.Handler {
 		},
 		SkipPaths: nil,
 		SkipFunc:  nil,
+		Clock:     time.Now,
 	}
 
 	for _, opt := range opts {
 		opt(c)
 	}
 
+	if err := validateFields(c.FieldsIn, c.FieldsOut); err != nil {
+		panic("logger: " + err.Error())
+	}
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			if shouldSkip(r, c) {
@@ -196,27 +324,113 @@ func New(opts ...Option) func(http.Handler) http.Handler {
 				return
 			}
 
-			start := time.Now()
+			start := c.Clock()
 			rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
 
-			ip := r.Header.Get("X-Real-IP")
-			if ip == "" {
-				ip, _, _ = net.SplitHostPort(r.RemoteAddr)
-			}
+			ip := httputil.ClientIP(r, c.TrustedProxies...)
 
-			c.Logger.LogAttrs(r.Context(), c.LevelRequestIn, "incoming request",
-				buildAttrs(c.FieldsIn, r, rw, ip, start)...,
-			)
+			if c.LogIncoming {
+				c.Logger.LogAttrs(r.Context(), c.LevelRequestIn, "incoming request",
+					truncateAttrs(buildAttrs(c.FieldsIn, r, rw, ip, start, c.Clock), c.MaxValueLength)...,
+				)
+			}
 
 			next.ServeHTTP(rw, r)
 
+			attrs := buildAttrs(c.FieldsOut, r, rw, ip, start, c.Clock)
+			for _, extract := range c.ContextAttrs {
+				attrs = append(attrs, extract(r.Context())...)
+			}
+
 			c.Logger.LogAttrs(r.Context(), c.LevelRequestOut, "request completed",
-				buildAttrs(c.FieldsOut, r, rw, ip, start)...,
+				truncateAttrs(attrs, c.MaxValueLength)...,
 			)
+
+			if c.Metrics != nil {
+				c.Metrics.ObserveRequest(r.Method, r.Pattern, rw.statusCode, c.Clock().Sub(start))
+			}
 		})
 	}
 }
 
+// validateFields returns an error for the first field that is not supported.
+func validateFields(fieldSets ...[]Field) error {
+	for _, fields := range fieldSets {
+		for _, f := range fields {
+			if _, ok := knownFields[f]; !ok {
+				return fmt.Errorf("unknown field %q", f)
+			}
+		}
+	}
+	return nil
+}
+
+// ellipsis marks a truncated value.
+const ellipsis = "…"
+
+// truncateAttrs truncates in place the string values of attrs, including the
+// ones nested in groups and string arrays, to n characters. It is a no-op when
+// n is not positive.
+func truncateAttrs(attrs []slog.Attr, n int) []slog.Attr {
+	if n <= 0 {
+		return attrs
+	}
+
+	for i, a := range attrs {
+		switch a.Value.Kind() {
+		case slog.KindString:
+			attrs[i].Value = slog.StringValue(truncate(a.Value.String(), n))
+		case slog.KindGroup:
+			group := slices.Clone(a.Value.Group())
+			attrs[i].Value = slog.GroupValue(truncateAttrs(group, n)...)
+		case slog.KindAny:
+			if values, ok := a.Value.Any().([]string); ok {
+				truncated := make([]string, len(values))
+				for j, v := range values {
+					truncated[j] = truncate(v, n)
+				}
+				attrs[i].Value = slog.AnyValue(truncated)
+			}
+		}
+	}
+
+	return attrs
+}
+
+// truncate returns s cut to n-1 characters followed by an ellipsis when it is
+// longer than n characters, s otherwise.
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+
+	cut, count := 0, 0
+	for i := range s {
+		if count == n-1 {
+			cut = i
+			break
+		}
+		count++
+	}
+
+	return s[:cut] + ellipsis
+}
+
+// queryParamAttrs returns one attribute per query parameter, sorted by key.
+// Repeated parameters are logged as a []string.
+func queryParamAttrs(query url.Values) []slog.Attr {
+	attrs := make([]slog.Attr, 0, len(query))
+	for _, key := range slices.Sorted(maps.Keys(query)) {
+		values := query[key]
+		if len(values) == 1 {
+			attrs = append(attrs, slog.String(key, values[0]))
+		} else {
+			attrs = append(attrs, slog.Any(key, values))
+		}
+	}
+	return attrs
+}
+
 func shouldSkip(r *http.Request, opt *config) bool {
 	if opt.SkipFunc != nil && opt.SkipFunc(r) {
 		return true
@@ -231,29 +445,66 @@ func shouldSkip(r *http.Request, opt *config) bool {
 	return false
 }
 
-func buildAttrs(fields []Field, r *http.Request, rw *responseWriter, ip string, start time.Time) []slog.Attr {
-	attrs := make([]slog.Attr, 0, len(fields))
+// buildAttrs returns the attributes for the given fields. The duration is
+// only measured when FieldDuration is requested, so that clock is not
+// called needlessly.
+func buildAttrs(fields []Field, r *http.Request, rw *responseWriter, ip string, start time.Time, clock func() time.Time) []slog.Attr {
+	var d time.Duration
+	if slices.Contains(fields, FieldDuration) {
+		d = clock().Sub(start)
+	}
 
+	attrs := make([]slog.Attr, 0, len(fields))
 	for _, f := range fields {
-		switch f {
-		case FieldMethod:
-			attrs = append(attrs, slog.String("method", r.Method))
-		case FieldPath:
-			attrs = append(attrs, slog.String("path", r.URL.Path))
-		case FieldQuery:
-			attrs = append(attrs, slog.String("query", r.URL.RawQuery))
-		case FieldIP:
-			attrs = append(attrs, slog.String("ip", ip))
-		case FieldUserAgent:
-			attrs = append(attrs, slog.String("userAgent", r.UserAgent()))
-		case FieldContentLength:
-			attrs = append(attrs, slog.Int64("contentLength", r.ContentLength))
-		case FieldStatus:
-			attrs = append(attrs, slog.Int("status", rw.statusCode))
-		case FieldDuration:
-			attrs = append(attrs, slog.Duration("duration", time.Since(start)))
+		if attr, ok := attrFor(f, r, ip, rw.statusCode, d); ok {
+			attrs = append(attrs, attr)
 		}
 	}
 
 	return attrs
 }
+
+// AttrFor returns the slog.Attr that the logging handler emits for field,
+// given the request, the response status code and the time it took to serve
+// the request. It reports false if field is unknown. It allows building log
+// records consistent with the handler's ones outside of it.
+//
+// The client IP is resolved by httputil.ClientIP without trusted proxies.
+//
+// Example:
+//
+//	attr, ok := logger.AttrFor(logger.FieldStatus, r, http.StatusOK, time.Since(start))
+func AttrFor(field Field, r *http.Request, status int, d time.Duration) (slog.Attr, bool) {
+	var ip string
+	if field == FieldIP {
+		ip = httputil.ClientIP(r)
+	}
+
+	return attrFor(field, r, ip, status, d)
+}
+
+// attrFor returns the slog.Attr for field, using ip as the client IP.
+func attrFor(field Field, r *http.Request, ip string, status int, d time.Duration) (slog.Attr, bool) {
+	switch field {
+	case FieldMethod:
+		return slog.String("method", r.Method), true
+	case FieldPath:
+		return slog.String("path", r.URL.Path), true
+	case FieldQuery:
+		return slog.String("query", r.URL.RawQuery), true
+	case FieldQueryParams:
+		return slog.Attr{Key: "queryParams", Value: slog.GroupValue(queryParamAttrs(r.URL.Query())...)}, true
+	case FieldIP:
+		return slog.String("ip", ip), true
+	case FieldUserAgent:
+		return slog.String("userAgent", r.UserAgent()), true
+	case FieldContentLength:
+		return slog.Int64("contentLength", r.ContentLength), true
+	case FieldStatus:
+		return slog.Int("status", status), true
+	case FieldDuration:
+		return slog.Duration("duration", d), true
+	default:
+		return slog.Attr{}, false
+	}
+}
@@ -1,15 +1,20 @@
 package logger
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"log/slog"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 
 	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
 )
 
 type mockLogger struct {
@@ -99,6 +104,84 @@ func TestWithSkipFunc(t *testing.T) {
 	assert.Equal(t, len(logger.entries), 0) // skipped
 }
 
+func TestWithLogIncoming(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected []string
+	}{
+		{name: "should log both entries by default", expected: []string{"incoming request", "request completed"}},
+		{name: "should log both entries when enabled", opts: []Option{WithLogIncoming(true)}, expected: []string{"incoming request", "request completed"}},
+		{name: "should log only the completion when disabled", opts: []Option{WithLogIncoming(false)}, expected: []string{"request completed"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := &mockLogger{}
+
+			mw := New(append([]Option{WithLogger(logger)}, tt.opts...)...)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			}))
+
+			w := httptest.NewRecorder()
+			mw.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+			assert.Equal(t, w.Code, http.StatusNoContent)
+			msgs := make([]string, len(logger.entries))
+			for i, e := range logger.entries {
+				msgs[i] = e.msg
+			}
+			assert.DeepEqual(t, msgs, tt.expected)
+		})
+	}
+}
+
+type observation struct {
+	Method   string
+	Route    string
+	Status   int
+	Duration time.Duration
+}
+
+type mockRecorder struct {
+	observations []observation
+}
+
+func (m *mockRecorder) ObserveRequest(method, route string, status int, d time.Duration) {
+	m.observations = append(m.observations, observation{Method: method, Route: route, Status: status, Duration: d})
+}
+
+func TestWithMetrics(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time {
+		now = now.Add(100 * time.Millisecond)
+		return now
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	recorder := &mockRecorder{}
+	handler := New(
+		WithLogger(Discard),
+		WithFieldsOut(FieldStatus),
+		WithClock(clock),
+		WithSkipPaths("/healthz"),
+		WithMetrics(recorder),
+	)(mux)
+
+	for _, target := range []string{"/users/42", "/missing", "/healthz"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	assert.DeepEqual(t, recorder.observations, []observation{
+		{Method: http.MethodGet, Route: "GET /users/{id}", Status: http.StatusAccepted, Duration: 100 * time.Millisecond},
+		{Method: http.MethodGet, Route: "", Status: http.StatusNotFound, Duration: 100 * time.Millisecond},
+	})
+}
+
 func TestCustomLevels(t *testing.T) {
 	logger := &mockLogger{}
 
@@ -121,6 +204,78 @@ func TestCustomLevels(t *testing.T) {
 	assert.Equal(t, logger.entries[1].level, slog.LevelError)
 }
 
+func TestWithTrustedProxies(t *testing.T) {
+	_, proxies, err := net.ParseCIDR("10.0.0.0/8")
+	assert.NilError(t, err)
+
+	logger := &mockLogger{}
+	handler := New(
+		WithLogger(logger),
+		WithFieldsIn(FieldIP),
+		WithFieldsOut(FieldIP),
+		WithTrustedProxies(proxies),
+	)(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.2")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, len(logger.entries), 2)
+	for _, entry := range logger.entries {
+		assert.Equal(t, entry.attrs[0].Value.String(), "203.0.113.7")
+	}
+}
+
+func TestNewPanicsOnUnknownField(t *testing.T) {
+	assert.Assert(t, cmp.Panics(func() {
+		New(WithFieldsIn(FieldMethod, Field("methdo")))
+	}))
+
+	assert.Assert(t, cmp.Panics(func() {
+		New(WithFieldsOut(Field("stauts")))
+	}))
+
+	defer func() {
+		r := recover()
+		assert.Equal(t, r, `logger: unknown field "stauts"`)
+	}()
+	New(WithFieldsOut(FieldStatus, Field("stauts")))
+}
+
+func TestValidateFields(t *testing.T) {
+	assert.NilError(t, validateFields(
+		[]Field{FieldMethod, FieldPath, FieldQuery, FieldIP, FieldUserAgent},
+		[]Field{FieldContentLength, FieldStatus, FieldDuration},
+	))
+	assert.ErrorContains(t, validateFields(nil, []Field{"foo"}), `unknown field "foo"`)
+}
+
+func TestWithClock(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time {
+		now = now.Add(150 * time.Millisecond)
+		return now
+	}
+
+	logger := &Capture{}
+	handler := New(
+		WithLogger(logger),
+		WithFieldsIn(FieldMethod),
+		WithFieldsOut(FieldDuration),
+		WithClock(clock),
+	)(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	entries := logger.Entries()
+	assert.Equal(t, len(entries), 2)
+
+	duration, ok := entries[1].Attr("duration")
+	assert.Assert(t, ok)
+	assert.Equal(t, duration.Value.Duration(), 150*time.Millisecond)
+}
+
 func TestBuildAttrsAllFields(t *testing.T) {
 	rw := &responseWriter{statusCode: 200}
 	r := httptest.NewRequest(http.MethodPut, "/all?foo=bar", nil)
@@ -136,7 +291,7 @@ func TestBuildAttrsAllFields(t *testing.T) {
 		FieldContentLength,
 		FieldStatus,
 		FieldDuration,
-	}, r, rw, "192.168.1.1", start)
+	}, r, rw, "192.168.1.1", start, time.Now)
 
 	expected := []string{"method", "path", "query", "ip", "userAgent", "contentLength", "status", "duration"}
 	for _, key := range expected {
@@ -144,6 +299,181 @@ func TestBuildAttrsAllFields(t *testing.T) {
 	}
 }
 
+func TestAttrFor(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest(http.MethodPost, "/users?page=2", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	r.Header.Set("User-Agent", "golazy-test")
+	r.ContentLength = 42
+
+	tests := []struct {
+		field         Field
+		expectedKey   string
+		expectedValue slog.Value
+	}{
+		{field: FieldMethod, expectedKey: "method", expectedValue: slog.StringValue(http.MethodPost)},
+		{field: FieldPath, expectedKey: "path", expectedValue: slog.StringValue("/users")},
+		{field: FieldQuery, expectedKey: "query", expectedValue: slog.StringValue("page=2")},
+		{field: FieldIP, expectedKey: "ip", expectedValue: slog.StringValue("10.0.0.1")},
+		{field: FieldUserAgent, expectedKey: "userAgent", expectedValue: slog.StringValue("golazy-test")},
+		{field: FieldContentLength, expectedKey: "contentLength", expectedValue: slog.Int64Value(42)},
+		{field: FieldStatus, expectedKey: "status", expectedValue: slog.IntValue(http.StatusCreated)},
+		{field: FieldDuration, expectedKey: "duration", expectedValue: slog.DurationValue(time.Second)},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.field), func(t *testing.T) {
+			t.Parallel()
+
+			attr, ok := AttrFor(tt.field, r, http.StatusCreated, time.Second)
+			assert.Assert(t, ok)
+			assert.Equal(t, attr.Key, tt.expectedKey)
+			assert.Assert(t, attr.Value.Equal(tt.expectedValue), "unexpected value %v", attr.Value)
+		})
+	}
+
+	t.Run("query params", func(t *testing.T) {
+		t.Parallel()
+
+		r := httptest.NewRequest(http.MethodGet, "/?tag=a&page=2&tag=b", nil)
+
+		attr, ok := AttrFor(FieldQueryParams, r, http.StatusOK, 0)
+		assert.Assert(t, ok)
+		assert.Equal(t, attr.Key, "queryParams")
+
+		group := attr.Value.Group()
+		assert.Equal(t, len(group), 2)
+		assert.Equal(t, group[0].Key, "page")
+		assert.Equal(t, group[0].Value.String(), "2")
+		assert.Equal(t, group[1].Key, "tag")
+		assert.DeepEqual(t, group[1].Value.Any(), []string{"a", "b"})
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		t.Parallel()
+
+		_, ok := AttrFor("foo", r, http.StatusOK, 0)
+		assert.Assert(t, !ok)
+	})
+
+	t.Run("every known field is mapped", func(t *testing.T) {
+		t.Parallel()
+
+		for f := range knownFields {
+			_, ok := AttrFor(f, r, http.StatusOK, 0)
+			assert.Assert(t, ok, "field %s", f)
+		}
+	})
+}
+
+func TestFieldQueryParams(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	handler := New(
+		WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))),
+		WithFieldsIn(FieldQueryParams),
+	)(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/?tag=a&tag=b&q=go", nil))
+
+	var entry struct {
+		QueryParams map[string]any `json:"queryParams"`
+	}
+	// the first line holds the incoming request entry
+	assert.NilError(t, json.NewDecoder(&buf).Decode(&entry))
+	assert.DeepEqual(t, entry.QueryParams, map[string]any{
+		"q":   "go",
+		"tag": []any{"a", "b"},
+	})
+}
+
+func TestWithContextAttrs(t *testing.T) {
+	t.Parallel()
+
+	type tenantKey struct{}
+
+	logger := &Capture{}
+	handler := New(
+		WithLogger(logger),
+		WithFieldsOut(FieldStatus),
+		WithContextAttrs(
+			func(ctx context.Context) []slog.Attr {
+				if v, ok := ctx.Value(tenantKey{}).(string); ok {
+					return []slog.Attr{slog.String("tenantID", v)}
+				}
+				return nil
+			},
+			func(_ context.Context) []slog.Attr { return nil },
+		),
+	)(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req = req.WithContext(context.WithValue(req.Context(), tenantKey{}, "acme"))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	entries := logger.Entries()
+	assert.Equal(t, len(entries), 2)
+
+	_, ok := entries[0].Attr("tenantID")
+	assert.Assert(t, !ok, "context attrs should only be added to the completion log")
+
+	tenant, ok := entries[1].Attr("tenantID")
+	assert.Assert(t, ok)
+	assert.Equal(t, tenant.Value.String(), "acme")
+
+	_, ok = entries[1].Attr("status")
+	assert.Assert(t, ok)
+}
+
+func TestWithMaxValueLength(t *testing.T) {
+	t.Parallel()
+
+	logger := &Capture{}
+	handler := New(
+		WithLogger(logger),
+		WithFieldsIn(FieldUserAgent, FieldQueryParams, FieldMethod),
+		WithFieldsOut(FieldPath, FieldStatus),
+		WithMaxValueLength(10),
+	)(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/a/very/long/path?tag=short&tag=averyveryverylongtag&q=0123456789", nil)
+	req.Header.Set("User-Agent", strings.Repeat("Mozilla/5.0 ", 100))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	entries := logger.Entries()
+	assert.Equal(t, len(entries), 2)
+
+	ua, ok := entries[0].Attr("userAgent")
+	assert.Assert(t, ok)
+	assert.Equal(t, ua.Value.String(), "Mozilla/5…")
+	assert.Equal(t, utf8.RuneCountInString(ua.Value.String()), 10)
+
+	method, _ := entries[0].Attr("method")
+	assert.Equal(t, method.Value.String(), http.MethodGet)
+
+	params, _ := entries[0].Attr("queryParams")
+	group := params.Value.Group()
+	assert.Equal(t, group[0].Value.String(), "0123456789")
+	assert.DeepEqual(t, group[1].Value.Any(), []string{"short", "averyvery…"})
+
+	path, _ := entries[1].Attr("path")
+	assert.Equal(t, path.Value.String(), "/a/very/l…")
+
+	status, _ := entries[1].Attr("status")
+	assert.Equal(t, status.Value.Int64(), int64(http.StatusOK))
+}
+
+func TestTruncate(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, truncate("hello", 5), "hello")
+	assert.Equal(t, truncate("hello!", 5), "hell…")
+	assert.Equal(t, truncate("héllo wörld", 6), "héllo…")
+	assert.Equal(t, truncate("hello", 1), "…")
+}
+
 func TestShouldSkip(t *testing.T) {
 	opt := &config{
 		SkipPaths: []string{"/skip"},
@@ -170,3 +500,20 @@ func hasAttr(attrs []slog.Attr, key string) bool {
 	}
 	return false
 }
+
+func TestDiscard(t *testing.T) {
+	var l Logger = Discard
+	l.LogAttrs(context.Background(), slog.LevelError, "discarded", slog.String("foo", "bar"))
+
+	called := false
+	handler := New(WithLogger(Discard))(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Assert(t, called)
+	assert.Equal(t, rec.Code, http.StatusOK)
+}
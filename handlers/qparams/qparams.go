@@ -72,7 +72,10 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"net/http"
+	"net/url"
+	"slices"
 	"strings"
 )
 
@@ -129,6 +132,8 @@ func (o RelationalOperator) Symbol() string {
 		return "ilike"
 	case InOperator:
 		return "in"
+	case BetweenOperator:
+		return "between"
 	default:
 		return "="
 	}
@@ -161,6 +166,10 @@ const (
 
 	// InOperator represents an inclusion check (IN).
 	InOperator RelationalOperator = "in"
+
+	// BetweenOperator represents an inclusive range check (BETWEEN). Its value
+	// holds the lower and upper bounds, e.g. [10, 20] or "10,20".
+	BetweenOperator RelationalOperator = "between"
 )
 
 var relationalOperators = map[RelationalOperator]struct{}{
@@ -173,6 +182,7 @@ var relationalOperators = map[RelationalOperator]struct{}{
 	LikeOperator:              {},
 	ILikeOperator:             {},
 	InOperator:                {},
+	BetweenOperator:           {},
 }
 
 // Filter represents a single filtering condition in a query.
@@ -182,6 +192,10 @@ var relationalOperators = map[RelationalOperator]struct{}{
 // Example:
 //
 //	{ "field": "name", "op": "eq", "value": "Alice" }
+//	{ "field": "age", "op": "gte", "value": 30 }
+//	{ "field": "id", "op": "in", "value": [1, 2, 3] }
+//	{ "field": "price", "op": "between", "value": [10, 20] }
+//	{ "field": "deleted_at", "op": "eq", "value": null }
 type Filter struct {
 	// Field is the name of the column or attribute being filtered.
 	Field string `json:"field"`
@@ -189,8 +203,13 @@ type Filter struct {
 	// Op is the relational operator to apply (e.g., eq, lt, in).
 	Op RelationalOperator `json:"op"`
 
-	// Value is the comparison value used with the operator.
-	Value string `json:"value"`
+	// Value is the comparison value used with the operator, kept as raw JSON
+	// to preserve its original type (string, number, boolean or array for in
+	// and between).
+	// Use the As* accessors to read it. A JSON null Value matches NULL columns
+	// and is only supported by the eq and ne operators, while a missing Value
+	// is rejected by validation.
+	Value json.RawMessage `json:"value"`
 }
 
 // FilterGroup represents a collection of filters combined together
@@ -279,6 +298,10 @@ type OrderClause struct {
 //	  "limit": 20,
 //	  "offset": 0
 //	}
+//
+// For simple queries, the payload may list filters at the top level instead,
+// e.g. {"filters":[...],"limit":20}. They are parsed as an implicit AND group,
+// which is combined (AND) with the root group when both are given.
 type SearchRequest struct {
 	// Groups represents the root filter group, which can contain
 	// multiple filters and nested groups combined with logical operators.
@@ -304,6 +327,10 @@ type contextKey string
 // objects are stored.
 const searchKey = contextKey("search")
 
+// configKey is the context key under which the SearchConfig of the
+// handler is stored.
+const configKey = contextKey("config")
+
 // ErrorHandler defines the signature of a function responsible
 // for handling request errors. It receives the HTTP response writer,
 // the request, and the encountered error.
@@ -320,11 +347,11 @@ var (
 
 	// defaultLogicalOperators defines the default set of logical
 	// operators allowed in filters.
-	defaultLogicalOperators = logicalOperators
+	defaultLogicalOperators = maps.Clone(logicalOperators)
 
 	// defaultRelationalOperators defines the default set of relational
 	// operators allowed in filters.
-	defaultRelationalOperators = relationalOperators
+	defaultRelationalOperators = maps.Clone(relationalOperators)
 
 	// defaultLimit defines the default maximum limit applied to
 	// search requests. Nil means "no limit".
@@ -349,6 +376,10 @@ var (
 	// defaultOrderFields defines the default set of fields
 	// allowed in order by.
 	defaultOrderFields = map[string]struct{}{}
+
+	// defaultRejectEmptyGroups indicates whether filter groups without
+	// filters and nested groups are rejected by default.
+	defaultRejectEmptyGroups = false
 )
 
 // SetDefaultQueryParam sets the default query parameter name
@@ -415,6 +446,12 @@ func SetDefaultOrderFields(fields ...string) {
 	}
 }
 
+// SetDefaultRejectEmptyGroups sets the global default for rejecting
+// filter groups that contain neither filters nor nested groups.
+func SetDefaultRejectEmptyGroups(reject bool) {
+	defaultRejectEmptyGroups = reject
+}
+
 // config stores the configuration for a search handler,
 // including query parameter names, validation rules,
 // allowed operators, limits, and error handling.
@@ -427,6 +464,51 @@ type config struct {
 	errorHandler               ErrorHandler
 	allowedFilterFields        map[string]struct{}
 	allowedOrderFields         map[string]struct{}
+	rejectEmptyGroups          bool
+	simpleParams               bool
+	searchTermParam            string
+	searchTermFields           []string
+	maxWindow                  *int
+	allowUnknownFields         bool
+	valueValidators            []func(f Filter) error
+	fieldEnums                 map[string]map[string]struct{}
+	defaultOperator            RelationalOperator
+}
+
+// SearchConfig is a read-only view of the effective configuration of a search
+// handler, meant for introspection (e.g. a "/schema" endpoint documenting the
+// search capabilities of a route). Slices are sorted and must not be modified.
+type SearchConfig struct {
+	// QueryParam is the name of the query parameter holding the JSON payload.
+	QueryParam string `json:"query_param"`
+
+	// SearchMandatory reports whether a search is required.
+	SearchMandatory bool `json:"search_mandatory"`
+
+	// LogicalOperators lists the allowed logical operators.
+	LogicalOperators []LogicalOperator `json:"logical_operators"`
+
+	// RelationalOperators lists the allowed relational operators.
+	RelationalOperators []RelationalOperator `json:"relational_operators"`
+
+	// FilterFields lists the fields allowed in filters.
+	FilterFields []string `json:"filter_fields"`
+
+	// OrderFields lists the fields allowed in order by clauses.
+	OrderFields []string `json:"order_fields"`
+
+	// FieldEnums lists, per field, the values allowed in filters.
+	FieldEnums map[string][]string `json:"field_enums,omitempty"`
+
+	// Limit is the maximum limit, nil meaning "no limit".
+	Limit *int `json:"limit,omitempty"`
+
+	// MaxWindow is the maximum offset + limit, nil meaning "no cap".
+	MaxWindow *int `json:"max_window,omitempty"`
+
+	// DefaultOperator is the relational operator used for filters without
+	// one, empty meaning that the operator is mandatory.
+	DefaultOperator RelationalOperator `json:"default_operator,omitempty"`
 }
 
 // Option is a functional option type used to configure Options
@@ -534,30 +616,145 @@ func WithExtraOrderFields(fields ...string) Option {
 	}
 }
 
+// WithRejectEmptyGroups configures whether filter groups that contain
+// neither filters nor nested groups are rejected. An empty group matches
+// everything, so rejecting it prevents accidental unfiltered queries.
+func WithRejectEmptyGroups(reject bool) Option {
+	return func(c *config) {
+		c.rejectEmptyGroups = reject
+	}
+}
+
+// WithSimpleParams configures whether simple "field=op:value" query
+// parameters (e.g. "?name=eq:Alice&age=gte:30") are parsed in addition to
+// the JSON search payload. Only parameters named after an allowed filter
+// field are considered and the operator defaults to eq when omitted
+// (e.g. "?name=Alice"). Values that contain a colon must then state the
+// operator explicitly (e.g. "?url=eq:http://example.com").
+// The resulting filters are combined in a flat AND
+// group, which is in turn combined (AND) with the JSON filter groups, if any.
+// Without a JSON search payload, the limit defaults to the one set by
+// WithLimit, capped by WithMaxWindow.
+func WithSimpleParams(enabled bool) Option {
+	return func(c *config) {
+		c.simpleParams = enabled
+	}
+}
+
+// WithSearchTerm configures a full-text style query parameter (e.g. "?search=foo")
+// that expands into an OR group of ilike filters matching "%foo%" over the given
+// fields. The group is combined (AND) with the JSON filter groups, if any.
+// Fields and the ilike operator are still subject to validation. Without a JSON
+// search payload, the limit defaults as described in WithSimpleParams.
+func WithSearchTerm(param string, fields ...string) Option {
+	return func(c *config) {
+		c.searchTermParam = param
+		c.searchTermFields = fields
+	}
+}
+
+// WithMaxWindow sets a hard cap on offset + limit, preventing deep pagination
+// that would scan the whole table. When set, limit becomes mandatory.
+// Negative values mean "no cap".
+func WithMaxWindow(n int) Option {
+	return func(c *config) {
+		if n < 0 {
+			c.maxWindow = nil
+		} else {
+			c.maxWindow = &n
+		}
+	}
+}
+
+// WithAllowUnknownFields configures whether unknown keys in the JSON search
+// payload are ignored instead of rejected. By default, they are rejected.
+// Allowing them lets clients send newer payloads to older servers.
+func WithAllowUnknownFields(allow bool) Option {
+	return func(c *config) {
+		c.allowUnknownFields = allow
+	}
+}
+
+// WithValueValidator adds a function invoked for every filter during validation,
+// after the built-in checks, letting callers enforce domain rules on values
+// (e.g. a status that must be one of a fixed set). The first error returned
+// aborts the validation and is passed to the error handler as is.
+// Multiple validators can be added and are applied in registration order.
+func WithValueValidator(validator func(f Filter) error) Option {
+	return func(c *config) {
+		c.valueValidators = append(c.valueValidators, validator)
+	}
+}
+
+// WithFieldEnum restricts the values allowed in filters on the given field.
+// For the in operator, every element of the list must be allowed. Null values
+// are not affected. Calling it again for the same field replaces the set.
+func WithFieldEnum(field string, allowed ...string) Option {
+	return func(c *config) {
+		if c.fieldEnums == nil {
+			c.fieldEnums = make(map[string]map[string]struct{})
+		}
+
+		values := make(map[string]struct{}, len(allowed))
+		for _, v := range allowed {
+			values[v] = struct{}{}
+		}
+		c.fieldEnums[field] = values
+	}
+}
+
+// WithDefaultOperator sets the relational operator applied to filters of the
+// JSON payload that omit it (e.g. {"field":"name","value":"Alice"}), before
+// validation; the operator must therefore be allowed. By default, it is empty
+// and filters without an operator are rejected.
+func WithDefaultOperator(op RelationalOperator) Option {
+	return func(c *config) {
+		c.defaultOperator = op
+	}
+}
+
 // NewSearchHandler creates a middleware that parses, validates,
 // and injects a SearchRequest into the request context.
 // It can be customized via Option functions, falling back to
-// global defaults when not provided.
+// global defaults when not provided. The defaults are copied, so that
+// options never affect them nor other handlers.
 func NewSearchHandler(opts ...Option) func(http.Handler) http.Handler {
 	c := &config{
 		queryParam:                 defaultQueryParam,
 		isSearchMandatory:          defaultSearchMandatory,
-		allowedLogicalOperators:    defaultLogicalOperators,
-		allowedRelationalOperators: defaultRelationalOperators,
+		allowedLogicalOperators:    maps.Clone(defaultLogicalOperators),
+		allowedRelationalOperators: maps.Clone(defaultRelationalOperators),
 		limit:                      defaultLimit,
 		errorHandler:               defaultErrorHandler,
-		allowedFilterFields:        defaultFilterFields,
-		allowedOrderFields:         defaultOrderFields,
+		allowedFilterFields:        maps.Clone(defaultFilterFields),
+		allowedOrderFields:         maps.Clone(defaultOrderFields),
+		rejectEmptyGroups:          defaultRejectEmptyGroups,
 	}
 
 	for _, opt := range opts {
 		opt(c)
 	}
 
+	view := c.view()
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			s := r.URL.Query().Get(c.queryParam)
-			if s == "" {
+			r = r.WithContext(context.WithValue(r.Context(), configKey, view))
+
+			query := r.URL.Query()
+			s := query.Get(c.queryParam)
+
+			var simpleFilters []Filter
+			if c.simpleParams {
+				simpleFilters = parseSimpleParams(query, c)
+			}
+
+			var term string
+			if c.searchTermParam != "" && len(c.searchTermFields) > 0 {
+				term = query.Get(c.searchTermParam)
+			}
+
+			if s == "" && len(simpleFilters) == 0 && term == "" {
 				if !c.isSearchMandatory {
 					next.ServeHTTP(w, r)
 					return
@@ -567,27 +764,197 @@ func NewSearchHandler(opts ...Option) func(http.Handler) http.Handler {
 				return
 			}
 
-			decoder := json.NewDecoder(strings.NewReader(s))
-			decoder.DisallowUnknownFields()
+			// without a JSON payload there is no way to set a limit, so the
+			// configured one is applied
+			search := &SearchRequest{Limit: implicitLimit(c)}
+			if s != "" {
+				var err error
+				if search, err = parse(s, c.allowUnknownFields); err != nil {
+					c.errorHandler(w, r, err)
+					return
+				}
 
-			var search SearchRequest
-			if err := decoder.Decode(&search); err != nil {
-				c.errorHandler(w, r, err)
-				return
+				if c.defaultOperator != "" {
+					setDefaultOperator(search.Groups, c.defaultOperator)
+				}
+			}
+
+			if len(simpleFilters) > 0 {
+				andGroup(search, FilterGroup{Op: AndOperator, Filters: simpleFilters})
 			}
 
-			if err := validateSearchRequest(&search, c); err != nil {
+			if term != "" {
+				andGroup(search, searchTermGroup(term, c.searchTermFields))
+			}
+
+			if err := validateSearchRequest(search, c); err != nil {
 				c.errorHandler(w, r, err)
 				return
 			}
 
-			ctx := context.WithValue(r.Context(), searchKey, &search)
+			ctx := context.WithValue(r.Context(), searchKey, search)
 
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
+// Parse decodes a raw JSON search payload into a SearchRequest, rejecting
+// unknown keys. Unlike NewSearchHandler, it does not validate fields, operators
+// or limits against any allowlist: validation is left to the caller.
+//
+// Example:
+//
+//	s, err := qparams.Parse(`{"groups":{"op":"and","filters":[{"field":"name","op":"eq","value":"Alice"}]}}`)
+func Parse(raw string) (*SearchRequest, error) {
+	return parse(raw, false)
+}
+
+// parse decodes a raw JSON search payload, optionally ignoring unknown keys.
+func parse(raw string, allowUnknownFields bool) (*SearchRequest, error) {
+	decoder := json.NewDecoder(strings.NewReader(raw))
+	if !allowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+
+	var payload searchPayload
+	if err := decoder.Decode(&payload); err != nil {
+		return nil, err
+	}
+
+	search := payload.SearchRequest
+	if len(payload.Filters) > 0 {
+		andGroup(&search, FilterGroup{Op: AndOperator, Filters: payload.Filters})
+	}
+
+	return &search, nil
+}
+
+// searchPayload is the JSON form of a SearchRequest, which additionally
+// accepts top-level filters as a shorthand for an AND root group.
+type searchPayload struct {
+	SearchRequest
+
+	Filters []Filter `json:"filters"`
+}
+
+// parseSimpleParams converts the simple "field=op:value" query parameters into
+// filters. Only parameters named after an allowed filter field are considered.
+// The text before the first ":" is used as operator when it is made of lowercase
+// letters only; otherwise the operator defaults to eq and the whole parameter
+// value is used as filter value (e.g. "12:30"). Operators are not checked here,
+// they are validated later like any other filter. Repeated parameters produce
+// one filter each. Fields are processed in lexical order to keep the result stable.
+func parseSimpleParams(query url.Values, opts *config) []Filter {
+	fields := make([]string, 0, len(query))
+	for field := range query {
+		if field == opts.queryParam || field == opts.searchTermParam {
+			continue
+		}
+
+		if _, ok := opts.allowedFilterFields[field]; ok {
+			fields = append(fields, field)
+		}
+	}
+	slices.Sort(fields)
+
+	var filters []Filter
+	for _, field := range fields {
+		for _, v := range query[field] {
+			f := Filter{Field: field, Op: EqualsOperator, Value: stringValue(v)}
+			if op, value, found := strings.Cut(v, ":"); found && isOperatorToken(op) {
+				f.Op = RelationalOperator(op)
+				f.Value = stringValue(value)
+			}
+			filters = append(filters, f)
+		}
+	}
+
+	return filters
+}
+
+// searchTermGroup builds an OR group of ilike filters matching term anywhere
+// in each of the given fields. The like wildcards contained in term are escaped
+// so that they are matched literally.
+func searchTermGroup(term string, fields []string) FilterGroup {
+	escaped := likeEscaper.Replace(term)
+
+	g := FilterGroup{Op: OrOperator, Filters: make([]Filter, 0, len(fields))}
+	for _, field := range fields {
+		g.Filters = append(g.Filters, Filter{Field: field, Op: ILikeOperator, Value: stringValue("%" + escaped + "%")})
+	}
+
+	return g
+}
+
+// likeEscaper escapes the special characters of like patterns.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// isOperatorToken reports whether s looks like a relational operator name.
+func isOperatorToken(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, r := range s {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// setDefaultOperator sets op on the filters of g and its nested groups that
+// have no operator.
+func setDefaultOperator(g *FilterGroup, op RelationalOperator) {
+	if g == nil {
+		return
+	}
+
+	for i := range g.Filters {
+		if g.Filters[i].Op == "" {
+			g.Filters[i].Op = op
+		}
+	}
+
+	for i := range g.Groups {
+		setDefaultOperator(&g.Groups[i], op)
+	}
+}
+
+// andGroup combines g with the root group of s using a logical AND.
+// If s has no root group, g becomes the root group.
+func andGroup(s *SearchRequest, g FilterGroup) {
+	if s.Groups == nil {
+		s.Groups = &g
+		return
+	}
+
+	s.Groups = &FilterGroup{
+		Op:     AndOperator,
+		Groups: []FilterGroup{*s.Groups, g},
+	}
+}
+
+// implicitLimit returns the limit applied to search requests built without a
+// JSON payload: the configured limit capped by the max window, or nil if
+// neither is set.
+func implicitLimit(c *config) *int {
+	var limit int
+	switch {
+	case c.limit != nil && c.maxWindow != nil:
+		limit = min(*c.limit, *c.maxWindow)
+	case c.limit != nil:
+		limit = *c.limit
+	case c.maxWindow != nil:
+		limit = *c.maxWindow
+	default:
+		return nil
+	}
+	return &limit
+}
+
 func validateSearchRequest(s *SearchRequest, opts *config) error {
 	// even though it is optional, if it is less than zero, it returns an error
 	if s.Limit != nil && *s.Limit < 0 {
@@ -608,6 +975,22 @@ func validateSearchRequest(s *SearchRequest, opts *config) error {
 		return errors.New("offset must be null or >= 0")
 	}
 
+	if opts.maxWindow != nil {
+		if s.Limit == nil {
+			return errors.New("limit is mandatory")
+		}
+
+		offset := 0
+		if s.Offset != nil {
+			offset = *s.Offset
+		}
+
+		// compared without summing, which could overflow
+		if *s.Limit > *opts.maxWindow || offset > *opts.maxWindow-*s.Limit {
+			return fmt.Errorf("offset + limit must be <= %d", *opts.maxWindow)
+		}
+	}
+
 	for _, o := range s.OrderBy {
 		if _, ok := opts.allowedOrderFields[o.Field]; !ok {
 			return fmt.Errorf("field %q not allowed in order by", o.Field)
@@ -624,6 +1007,10 @@ func validateSearchRequest(s *SearchRequest, opts *config) error {
 			return fmt.Errorf("logical operator %q not allowed", g.Op)
 		}
 
+		if opts.rejectEmptyGroups && len(g.Filters) == 0 && len(g.Groups) == 0 {
+			return errors.New("filter group must contain at least one filter or group")
+		}
+
 		for _, f := range g.Filters {
 			if _, ok := opts.allowedFilterFields[f.Field]; !ok {
 				return fmt.Errorf("field %q not allowed in filters", f.Field)
@@ -632,6 +1019,20 @@ func validateSearchRequest(s *SearchRequest, opts *config) error {
 			if _, ok := opts.allowedRelationalOperators[f.Op]; !ok {
 				return fmt.Errorf("relational operator %q not allowed for field %q", f.Op, f.Field)
 			}
+
+			if err := validateValue(f); err != nil {
+				return err
+			}
+
+			if err := validateEnum(f, opts.fieldEnums); err != nil {
+				return err
+			}
+
+			for _, validator := range opts.valueValidators {
+				if err := validator(f); err != nil {
+					return err
+				}
+			}
 		}
 
 		for _, sg := range g.Groups {
@@ -650,6 +1051,87 @@ func validateSearchRequest(s *SearchRequest, opts *config) error {
 	return nil
 }
 
+// validateEnum checks that the value of f, or each of its elements for the in
+// operator, belongs to the allowed values of its field, if any.
+func validateEnum(f Filter, enums map[string]map[string]struct{}) error {
+	allowed, ok := enums[f.Field]
+	if !ok || f.IsNull() {
+		return nil
+	}
+
+	var values []string
+	if f.Op == InOperator || f.Op == BetweenOperator {
+		var err error
+		if values, err = f.AsStringSlice(); err != nil {
+			return err
+		}
+	} else {
+		v, err := f.AsString()
+		if err != nil {
+			return err
+		}
+		values = []string{v}
+	}
+
+	for _, v := range values {
+		if _, ok := allowed[v]; !ok {
+			return fmt.Errorf("value %q not allowed for field %q", v, f.Field)
+		}
+	}
+
+	return nil
+}
+
+// view returns a snapshot of c as a SearchConfig.
+func (c *config) view() *SearchConfig {
+	v := &SearchConfig{
+		QueryParam:          c.queryParam,
+		SearchMandatory:     c.isSearchMandatory,
+		LogicalOperators:    sortedKeys(c.allowedLogicalOperators),
+		RelationalOperators: sortedKeys(c.allowedRelationalOperators),
+		FilterFields:        sortedKeys(c.allowedFilterFields),
+		OrderFields:         sortedKeys(c.allowedOrderFields),
+		DefaultOperator:     c.defaultOperator,
+	}
+
+	if c.limit != nil {
+		limit := *c.limit
+		v.Limit = &limit
+	}
+
+	if c.maxWindow != nil {
+		maxWindow := *c.maxWindow
+		v.MaxWindow = &maxWindow
+	}
+
+	if len(c.fieldEnums) > 0 {
+		v.FieldEnums = make(map[string][]string, len(c.fieldEnums))
+		for field, values := range c.fieldEnums {
+			v.FieldEnums[field] = sortedKeys(values)
+		}
+	}
+
+	return v
+}
+
+// sortedKeys returns the keys of m in ascending order.
+func sortedKeys[K ~string](m map[K]struct{}) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// GetSearchConfig retrieves the SearchConfig of the search handler that
+// processed the request. It is available even when no search was provided.
+// If the request did not go through a search handler, it returns nil.
+func GetSearchConfig(r *http.Request) *SearchConfig {
+	v, _ := r.Context().Value(configKey).(*SearchConfig)
+	return v
+}
+
 // GetSearchRequest retrieves the parsed SearchRequest stored in the
 // request context by NewSearchHandler. If no request is stored, it
 // returns nil.
@@ -666,3 +1148,29 @@ func GetSearchRequest(r *http.Request) *SearchRequest {
 
 	return s
 }
+
+// EncodeSearchRequest is the inverse of the parsing performed by NewSearchHandler.
+// It marshals the given SearchRequest to JSON and encodes it as a URL query
+// string under the given query parameter (e.g. "q=%7B%22limit%22%3A10%7D").
+// If queryParam is empty, the default query parameter is used.
+//
+// Example:
+//
+//	qs, err := qparams.EncodeSearchRequest(s, "q")
+//	next := "/api/v1/users?" + qs
+func EncodeSearchRequest(s *SearchRequest, queryParam string) (string, error) {
+	if s == nil {
+		return "", errors.New("search request must not be nil")
+	}
+
+	if queryParam == "" {
+		queryParam = defaultQueryParam
+	}
+
+	b, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+
+	return url.Values{queryParam: []string{string(b)}}.Encode(), nil
+}
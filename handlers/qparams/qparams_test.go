@@ -2,9 +2,15 @@ package qparams
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"maps"
+	"math"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"slices"
 	"testing"
 
 	"github.com/paccolamano/golazy/utility"
@@ -96,6 +102,11 @@ func TestRelationalOperatorSymbol(t *testing.T) {
 			operator: InOperator,
 			expected: "in",
 		},
+		{
+			name:     `Symbol() should return "between"`,
+			operator: BetweenOperator,
+			expected: "between",
+		},
 		{
 			name:     `Given wrong operator, Symbol() should return "="`,
 			operator: RelationalOperator("foo"),
@@ -163,7 +174,7 @@ func TestSetDefaultSearchMandatory(t *testing.T) {
 }
 
 func TestSetDefaultLogicalOperators(t *testing.T) {
-	original := defaultLogicalOperators
+	original := maps.Clone(defaultLogicalOperators)
 	defer func() {
 		defaultLogicalOperators = original
 	}()
@@ -173,7 +184,7 @@ func TestSetDefaultLogicalOperators(t *testing.T) {
 }
 
 func TestSetDefaultRelationalOperators(t *testing.T) {
-	original := defaultRelationalOperators
+	original := maps.Clone(defaultRelationalOperators)
 	defer func() {
 		defaultRelationalOperators = original
 	}()
@@ -215,7 +226,7 @@ func TestSetDefaultErrorHandler(t *testing.T) {
 }
 
 func TestSetDefaultFilterFields(t *testing.T) {
-	original := defaultFilterFields
+	original := maps.Clone(defaultFilterFields)
 	defer func() {
 		defaultFilterFields = original
 	}()
@@ -226,7 +237,7 @@ func TestSetDefaultFilterFields(t *testing.T) {
 }
 
 func TestSetDefaultOrderFields(t *testing.T) {
-	original := defaultOrderFields
+	original := maps.Clone(defaultOrderFields)
 	defer func() {
 		defaultOrderFields = original
 	}()
@@ -236,6 +247,16 @@ func TestSetDefaultOrderFields(t *testing.T) {
 	assert.DeepEqual(t, defaultOrderFields, map[string]struct{}{"id": {}})
 }
 
+func TestSetDefaultRejectEmptyGroups(t *testing.T) {
+	original := defaultRejectEmptyGroups
+	defer func() {
+		defaultRejectEmptyGroups = original
+	}()
+
+	SetDefaultRejectEmptyGroups(true)
+	assert.Equal(t, defaultRejectEmptyGroups, true)
+}
+
 func TestWithQueryParam(t *testing.T) {
 	t.Parallel()
 
@@ -360,6 +381,245 @@ func TestWithExtraOrderFields(t *testing.T) {
 	assert.DeepEqual(t, opts.allowedOrderFields, map[string]struct{}{"id": {}, "name": {}})
 }
 
+func TestWithRejectEmptyGroups(t *testing.T) {
+	t.Parallel()
+
+	opts := config{}
+	f := WithRejectEmptyGroups(true)
+	f(&opts)
+
+	assert.Equal(t, opts.rejectEmptyGroups, true)
+}
+
+func TestWithMaxWindow(t *testing.T) {
+	t.Parallel()
+
+	opts := config{}
+	f := WithMaxWindow(1000)
+	f(&opts)
+
+	assert.Equal(t, *opts.maxWindow, 1000)
+
+	f = WithMaxWindow(-1)
+	f(&opts)
+
+	assert.Assert(t, opts.maxWindow == nil)
+}
+
+func TestWithAllowUnknownFields(t *testing.T) {
+	t.Parallel()
+
+	c := &config{}
+	opt := WithAllowUnknownFields(true)
+	opt(c)
+
+	assert.Equal(t, c.allowUnknownFields, true)
+}
+
+func TestWithValueValidator(t *testing.T) {
+	t.Parallel()
+
+	c := &config{}
+	WithValueValidator(func(Filter) error { return nil })(c)
+	WithValueValidator(func(Filter) error { return nil })(c)
+
+	assert.Equal(t, len(c.valueValidators), 2)
+}
+
+func TestWithFieldEnum(t *testing.T) {
+	t.Parallel()
+
+	c := &config{}
+	WithFieldEnum("status", "active", "inactive")(c)
+	WithFieldEnum("role", "admin")(c)
+
+	assert.DeepEqual(t, c.fieldEnums, map[string]map[string]struct{}{
+		"status": {"active": {}, "inactive": {}},
+		"role":   {"admin": {}},
+	})
+}
+
+func TestWithDefaultOperator(t *testing.T) {
+	t.Parallel()
+
+	opts := config{}
+	f := WithDefaultOperator(EqualsOperator)
+	f(&opts)
+
+	assert.Equal(t, opts.defaultOperator, EqualsOperator)
+}
+
+func TestSetDefaultOperator(t *testing.T) {
+	t.Parallel()
+
+	g := &FilterGroup{
+		Op: AndOperator,
+		Filters: []Filter{
+			{Field: "name", Value: stringValue("Alice")},
+			{Field: "age", Op: GreaterThanOperator, Value: json.RawMessage(`30`)},
+		},
+		Groups: []FilterGroup{
+			{Op: OrOperator, Filters: []Filter{{Field: "role", Value: stringValue("admin")}}},
+		},
+	}
+
+	setDefaultOperator(g, EqualsOperator)
+	setDefaultOperator(nil, EqualsOperator)
+
+	assert.Equal(t, g.Filters[0].Op, EqualsOperator)
+	assert.Equal(t, g.Filters[1].Op, GreaterThanOperator)
+	assert.Equal(t, g.Groups[0].Filters[0].Op, EqualsOperator)
+}
+
+func TestValidateEnum(t *testing.T) {
+	t.Parallel()
+
+	enums := map[string]map[string]struct{}{
+		"status": {"active": {}, "inactive": {}},
+		"level":  {"1": {}, "2": {}},
+	}
+
+	tests := []struct {
+		name        string
+		filter      Filter
+		expectedErr string
+	}{
+		{
+			name:   "with allowed value",
+			filter: Filter{Field: "status", Op: EqualsOperator, Value: stringValue("active")},
+		},
+		{
+			name:        "with disallowed value",
+			filter:      Filter{Field: "status", Op: EqualsOperator, Value: stringValue("deleted")},
+			expectedErr: `value "deleted" not allowed for field "status"`,
+		},
+		{
+			name:   "with allowed in list",
+			filter: Filter{Field: "status", Op: InOperator, Value: json.RawMessage(`["active","inactive"]`)},
+		},
+		{
+			name:        "with disallowed value in list",
+			filter:      Filter{Field: "status", Op: InOperator, Value: json.RawMessage(`["active","deleted"]`)},
+			expectedErr: `value "deleted" not allowed for field "status"`,
+		},
+		{
+			name:        "with disallowed value in comma separated list",
+			filter:      Filter{Field: "status", Op: InOperator, Value: stringValue("active, deleted")},
+			expectedErr: `value "deleted" not allowed for field "status"`,
+		},
+		{
+			name:   "with numeric value",
+			filter: Filter{Field: "level", Op: EqualsOperator, Value: json.RawMessage(`2`)},
+		},
+		{
+			name:   "with null value",
+			filter: Filter{Field: "status", Op: EqualsOperator, Value: json.RawMessage(`null`)},
+		},
+		{
+			name:   "with field without enum",
+			filter: Filter{Field: "name", Op: EqualsOperator, Value: stringValue("anything")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateEnum(tt.filter, enums)
+			if tt.expectedErr == "" {
+				assert.NilError(t, err)
+				return
+			}
+			assert.Error(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestWithSimpleParams(t *testing.T) {
+	t.Parallel()
+
+	opts := config{}
+	f := WithSimpleParams(true)
+	f(&opts)
+
+	assert.Equal(t, opts.simpleParams, true)
+}
+
+func TestWithSearchTerm(t *testing.T) {
+	t.Parallel()
+
+	opts := config{}
+	f := WithSearchTerm("search", "name", "email")
+	f(&opts)
+
+	assert.Equal(t, opts.searchTermParam, "search")
+	assert.DeepEqual(t, opts.searchTermFields, []string{"name", "email"})
+}
+
+func TestSearchTermGroup(t *testing.T) {
+	t.Parallel()
+
+	g := searchTermGroup("fo%o_", []string{"name", "email"})
+
+	assert.DeepEqual(t, g, FilterGroup{
+		Op: OrOperator,
+		Filters: []Filter{
+			{Field: "name", Op: ILikeOperator, Value: stringValue(`%fo\%o\_%`)},
+			{Field: "email", Op: ILikeOperator, Value: stringValue(`%fo\%o\_%`)},
+		},
+	})
+}
+
+func TestParseSimpleParams(t *testing.T) {
+	t.Parallel()
+
+	opts := &config{
+		queryParam:          "q",
+		allowedFilterFields: map[string]struct{}{"name": {}, "age": {}, "q": {}},
+	}
+
+	query, err := url.ParseQuery("name=eq:Alice&age=gte:30&age=lt:65&status=active&q={}&name=Bob&name=12:30&name=Foo:bar")
+	assert.NilError(t, err)
+
+	expected := []Filter{
+		{Field: "age", Op: GreaterThanEqualsOperator, Value: stringValue("30")},
+		{Field: "age", Op: LowerThanOperator, Value: stringValue("65")},
+		{Field: "name", Op: EqualsOperator, Value: stringValue("Alice")},
+		{Field: "name", Op: EqualsOperator, Value: stringValue("Bob")},
+		{Field: "name", Op: EqualsOperator, Value: stringValue("12:30")},
+		{Field: "name", Op: EqualsOperator, Value: stringValue("Foo:bar")},
+	}
+	assert.DeepEqual(t, parseSimpleParams(query, opts), expected)
+}
+
+func TestAndGroup(t *testing.T) {
+	t.Parallel()
+
+	simple := FilterGroup{
+		Op:      AndOperator,
+		Filters: []Filter{{Field: "name", Op: EqualsOperator, Value: stringValue("Alice")}},
+	}
+
+	t.Run("andGroup() should set the root group", func(t *testing.T) {
+		s := SearchRequest{}
+		andGroup(&s, simple)
+
+		assert.DeepEqual(t, s.Groups, &simple)
+	})
+
+	t.Run("andGroup() should combine with the root group", func(t *testing.T) {
+		root := FilterGroup{
+			Op:      OrOperator,
+			Filters: []Filter{{Field: "id", Op: EqualsOperator, Value: stringValue("1")}},
+		}
+		s := SearchRequest{Groups: &root}
+		andGroup(&s, simple)
+
+		assert.DeepEqual(t, s.Groups, &FilterGroup{
+			Op:     AndOperator,
+			Groups: []FilterGroup{root, simple},
+		})
+	})
+}
+
 func TestNewSearchHandler(t *testing.T) {
 	t.Parallel()
 
@@ -407,6 +667,82 @@ func TestNewSearchHandler(t *testing.T) {
 				assert.Equal(t, res.Code, http.StatusBadRequest)
 			},
 		},
+		{
+			name: "with simple params only",
+			path: "/search?unknown=eq:foo",
+			handler: NewSearchHandler(WithSimpleParams(true))(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+				t.Errorf("next handler should not be called when no filter field is allowed")
+			})),
+			check: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, res.Code, http.StatusBadRequest)
+			},
+		},
+		{
+			name: "with simple params and limit",
+			path: "/search?status=active",
+			handler: NewSearchHandler(WithSimpleParams(true), WithFilterFields("status"), WithLimit(50))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.DeepEqual(t, GetSearchRequest(r).Limit, utility.Ptr(50))
+				w.WriteHeader(http.StatusOK)
+			})),
+			check: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, res.Code, http.StatusOK)
+			},
+		},
+		{
+			name: "with search term and max window",
+			path: "/search?search=foo",
+			handler: NewSearchHandler(WithSearchTerm("search", "name"), WithFilterFields("name"), WithLimit(50), WithMaxWindow(20))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.DeepEqual(t, GetSearchRequest(r).Limit, utility.Ptr(20))
+				w.WriteHeader(http.StatusOK)
+			})),
+			check: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, res.Code, http.StatusOK)
+			},
+		},
+		{
+			name: "with unknown field",
+			path: `/search?q={"limit":10,"unknown":true}`,
+			handler: NewSearchHandler()(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+				t.Errorf("next handler should not be called when JSON has unknown fields")
+			})),
+			check: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, res.Code, http.StatusBadRequest)
+			},
+		},
+		{
+			name: "with unknown field allowed",
+			path: `/search?q={"limit":10,"unknown":true}`,
+			handler: NewSearchHandler(WithAllowUnknownFields(true))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.DeepEqual(t, GetSearchRequest(r), &SearchRequest{Limit: utility.Ptr(10)})
+				w.WriteHeader(http.StatusOK)
+			})),
+			check: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, res.Code, http.StatusOK)
+			},
+		},
+		{
+			name: "with omitted operator",
+			path: `/search?q={"filters":[{"field":"name","value":"Alice"}]}`,
+			handler: NewSearchHandler(WithFilterFields("name"))(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+				t.Errorf("next handler should not be called when the operator is missing")
+			})),
+			check: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, res.Code, http.StatusBadRequest)
+			},
+		},
+		{
+			name: "with omitted operator and default operator",
+			path: `/search?q={"filters":[{"field":"name","value":"Alice"}]}`,
+			handler: NewSearchHandler(WithFilterFields("name"), WithDefaultOperator(EqualsOperator))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.DeepEqual(t, GetSearchRequest(r).Groups.Filters, []Filter{
+					{Field: "name", Op: EqualsOperator, Value: stringValue("Alice")},
+				})
+				w.WriteHeader(http.StatusOK)
+			})),
+			check: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, res.Code, http.StatusOK)
+			},
+		},
 		{
 			name: "with valid request",
 			path: `/search?q={"limit":10,"offset":0}`,
@@ -438,6 +774,67 @@ func TestNewSearchHandler(t *testing.T) {
 	}
 }
 
+func TestNewSearchHandlerIsolation(t *testing.T) {
+	t.Parallel()
+
+	filterFields := func(t *testing.T, mw func(http.Handler) http.Handler) []string {
+		t.Helper()
+
+		var got *SearchConfig
+		mw(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			got = GetSearchConfig(r)
+		})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+		return got.FilterFields
+	}
+
+	users := NewSearchHandler(WithSearchMandatory(false), WithExtraFilterFields("email"), WithExtraOrderFields("email"))
+	orders := NewSearchHandler(WithSearchMandatory(false), WithExtraFilterFields("total"))
+
+	assert.Assert(t, slices.Contains(filterFields(t, users), "email"))
+	assert.Assert(t, !slices.Contains(filterFields(t, users), "total"))
+	assert.Assert(t, slices.Contains(filterFields(t, orders), "total"))
+	assert.Assert(t, !slices.Contains(filterFields(t, orders), "email"))
+
+	_, ok := defaultFilterFields["email"]
+	assert.Assert(t, !ok, "extra filter fields should not leak into the defaults")
+	_, ok = defaultOrderFields["email"]
+	assert.Assert(t, !ok, "extra order fields should not leak into the defaults")
+}
+
+func TestNewSearchHandlerDefaultsUnchanged(t *testing.T) {
+	t.Parallel()
+
+	config := func(t *testing.T, opts ...Option) *SearchConfig {
+		t.Helper()
+
+		var got *SearchConfig
+		NewSearchHandler(append(opts, WithSearchMandatory(false))...)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			got = GetSearchConfig(r)
+		})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+		return got
+	}
+
+	before := config(t)
+
+	custom := config(t,
+		WithExtraFilterFields("secret"),
+		WithExtraOrderFields("secret"),
+		WithLogicalOperators(AndOperator),
+		WithRelationalOperators(EqualsOperator),
+	)
+	assert.DeepEqual(t, custom.LogicalOperators, []LogicalOperator{AndOperator})
+	assert.DeepEqual(t, custom.RelationalOperators, []RelationalOperator{EqualsOperator})
+
+	after := config(t)
+	assert.DeepEqual(t, after, before)
+	assert.Assert(t, !slices.Contains(after.FilterFields, "secret"))
+	assert.Assert(t, !slices.Contains(after.OrderFields, "secret"))
+	assert.Equal(t, len(logicalOperators), 2)
+	assert.Equal(t, len(after.RelationalOperators), len(relationalOperators))
+}
+
 func TestValidateSearchRequest(t *testing.T) {
 	t.Parallel()
 
@@ -491,6 +888,69 @@ func TestValidateSearchRequest(t *testing.T) {
 				assert.ErrorContains(t, err, `offset must be null or >= 0`)
 			},
 		},
+		{
+			name: "with window over the cap",
+			search: SearchRequest{
+				Limit:  utility.Ptr(50),
+				Offset: utility.Ptr(980),
+			},
+			opts: config{
+				maxWindow: utility.Ptr(1000),
+			},
+			check: func(t *testing.T, err error) {
+				assert.ErrorContains(t, err, `offset + limit must be <= 1000`)
+			},
+		},
+		{
+			name: "with offset overflowing the window",
+			search: SearchRequest{
+				Limit:  utility.Ptr(50),
+				Offset: utility.Ptr(math.MaxInt),
+			},
+			opts: config{
+				maxWindow: utility.Ptr(1000),
+			},
+			check: func(t *testing.T, err error) {
+				assert.ErrorContains(t, err, `offset + limit must be <= 1000`)
+			},
+		},
+		{
+			name: "with limit over the window",
+			search: SearchRequest{
+				Limit: utility.Ptr(1001),
+			},
+			opts: config{
+				maxWindow: utility.Ptr(1000),
+			},
+			check: func(t *testing.T, err error) {
+				assert.ErrorContains(t, err, `offset + limit must be <= 1000`)
+			},
+		},
+		{
+			name: "with window within the cap",
+			search: SearchRequest{
+				Limit:  utility.Ptr(50),
+				Offset: utility.Ptr(950),
+			},
+			opts: config{
+				maxWindow: utility.Ptr(1000),
+			},
+			check: func(t *testing.T, err error) {
+				assert.NilError(t, err)
+			},
+		},
+		{
+			name: "with null limit when window is capped",
+			search: SearchRequest{
+				Offset: utility.Ptr(10),
+			},
+			opts: config{
+				maxWindow: utility.Ptr(1000),
+			},
+			check: func(t *testing.T, err error) {
+				assert.ErrorContains(t, err, `limit is mandatory`)
+			},
+		},
 		{
 			name: "with not allowed order field",
 			search: SearchRequest{
@@ -511,7 +971,7 @@ func TestValidateSearchRequest(t *testing.T) {
 				Groups: &FilterGroup{
 					Op: AndOperator,
 					Filters: []Filter{
-						{Field: "notAllowedField", Op: EqualsOperator, Value: "foo"},
+						{Field: "notAllowedField", Op: EqualsOperator, Value: stringValue("foo")},
 					},
 				},
 			},
@@ -530,7 +990,7 @@ func TestValidateSearchRequest(t *testing.T) {
 				Groups: &FilterGroup{
 					Op: AndOperator,
 					Filters: []Filter{
-						{Field: "name", Op: NotEqualsOperator, Value: "foo"},
+						{Field: "name", Op: NotEqualsOperator, Value: stringValue("foo")},
 					},
 				},
 			},
@@ -543,13 +1003,80 @@ func TestValidateSearchRequest(t *testing.T) {
 				assert.ErrorContains(t, err, `relational operator "ne" not allowed for field "name"`)
 			},
 		},
+		{
+			name: "with null value and unsupported operator",
+			search: SearchRequest{
+				Groups: &FilterGroup{
+					Op: AndOperator,
+					Filters: []Filter{
+						{Field: "name", Op: GreaterThanOperator, Value: json.RawMessage(`null`)},
+					},
+				},
+			},
+			opts: config{
+				allowedLogicalOperators:    map[LogicalOperator]struct{}{AndOperator: {}},
+				allowedRelationalOperators: map[RelationalOperator]struct{}{GreaterThanOperator: {}},
+				allowedFilterFields:        map[string]struct{}{"name": {}},
+			},
+			check: func(t *testing.T, err error) {
+				assert.ErrorContains(t, err, `relational operator "gt" does not support null value for field "name"`)
+			},
+		},
+		{
+			name: "with null value and eq operator",
+			search: SearchRequest{
+				Groups: &FilterGroup{
+					Op: AndOperator,
+					Filters: []Filter{
+						{Field: "name", Op: EqualsOperator, Value: json.RawMessage(`null`)},
+					},
+				},
+			},
+			opts: config{
+				allowedLogicalOperators:    map[LogicalOperator]struct{}{AndOperator: {}},
+				allowedRelationalOperators: map[RelationalOperator]struct{}{EqualsOperator: {}},
+				allowedFilterFields:        map[string]struct{}{"name": {}},
+			},
+			check: func(t *testing.T, err error) {
+				assert.NilError(t, err)
+			},
+		},
+		{
+			name: "with value rejected by validator",
+			search: SearchRequest{
+				Groups: &FilterGroup{
+					Op: AndOperator,
+					Filters: []Filter{
+						{Field: "status", Op: EqualsOperator, Value: stringValue("active")},
+						{Field: "status", Op: EqualsOperator, Value: stringValue("unknown")},
+					},
+				},
+			},
+			opts: config{
+				allowedLogicalOperators:    map[LogicalOperator]struct{}{AndOperator: {}},
+				allowedRelationalOperators: map[RelationalOperator]struct{}{EqualsOperator: {}},
+				allowedFilterFields:        map[string]struct{}{"status": {}},
+				valueValidators: []func(f Filter) error{
+					func(f Filter) error {
+						v, _ := f.AsString()
+						if f.Field == "status" && v != "active" && v != "inactive" {
+							return fmt.Errorf("invalid status %q", v)
+						}
+						return nil
+					},
+				},
+			},
+			check: func(t *testing.T, err error) {
+				assert.Error(t, err, `invalid status "unknown"`)
+			},
+		},
 		{
 			name: "with not allowed logical operator",
 			search: SearchRequest{
 				Groups: &FilterGroup{
 					Op: AndOperator,
 					Filters: []Filter{
-						{Field: "name", Op: NotEqualsOperator, Value: "foo"},
+						{Field: "name", Op: NotEqualsOperator, Value: stringValue("foo")},
 					},
 				},
 			},
@@ -562,6 +1089,70 @@ func TestValidateSearchRequest(t *testing.T) {
 				assert.ErrorContains(t, err, `logical operator "and" not allowed`)
 			},
 		},
+		{
+			name: "with empty group when rejected",
+			search: SearchRequest{
+				Groups: &FilterGroup{
+					Op: AndOperator,
+				},
+			},
+			opts: config{
+				allowedLogicalOperators: logicalOperators,
+				rejectEmptyGroups:       true,
+			},
+			check: func(t *testing.T, err error) {
+				assert.ErrorContains(t, err, `filter group must contain at least one filter or group`)
+			},
+		},
+		{
+			name: "with empty nested group when rejected",
+			search: SearchRequest{
+				Groups: &FilterGroup{
+					Op: AndOperator,
+					Filters: []Filter{
+						{Field: "name", Op: EqualsOperator, Value: stringValue("foo")},
+					},
+					Groups: []FilterGroup{
+						{Op: AndOperator},
+					},
+				},
+			},
+			opts: config{
+				allowedLogicalOperators:    logicalOperators,
+				allowedRelationalOperators: relationalOperators,
+				allowedFilterFields:        map[string]struct{}{"name": {}},
+				rejectEmptyGroups:          true,
+			},
+			check: func(t *testing.T, err error) {
+				assert.ErrorContains(t, err, `filter group must contain at least one filter or group`)
+			},
+		},
+		{
+			name: "with empty group when allowed",
+			search: SearchRequest{
+				Groups: &FilterGroup{
+					Op: AndOperator,
+				},
+			},
+			opts: config{
+				allowedLogicalOperators: logicalOperators,
+			},
+			check: func(t *testing.T, err error) {
+				assert.NilError(t, err)
+			},
+		},
+		{
+			name: "with nil group when rejected",
+			search: SearchRequest{
+				Groups: nil,
+			},
+			opts: config{
+				rejectEmptyGroups: true,
+			},
+			check: func(t *testing.T, err error) {
+				assert.NilError(t, err)
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -614,3 +1205,219 @@ func TestGetSearchRequest(t *testing.T) {
 		assert.Equal(t, s, expected)
 	})
 }
+
+func TestEncodeSearchRequest(t *testing.T) {
+	t.Parallel()
+
+	t.Run("EncodeSearchRequest() should fail due to nil search request", func(t *testing.T) {
+		_, err := EncodeSearchRequest(nil, "q")
+		assert.ErrorContains(t, err, "search request must not be nil")
+	})
+
+	t.Run("EncodeSearchRequest() should use the given query param", func(t *testing.T) {
+		qs, err := EncodeSearchRequest(&SearchRequest{Limit: utility.Ptr(10)}, "search")
+		assert.NilError(t, err)
+		assert.Equal(t, qs, "search=%7B%22limit%22%3A10%7D")
+	})
+
+	t.Run("EncodeSearchRequest() should round-trip through parsing", func(t *testing.T) {
+		expected := &SearchRequest{
+			Groups: &FilterGroup{
+				Op: AndOperator,
+				Filters: []Filter{
+					{Field: "name", Op: ILikeOperator, Value: stringValue("%a&b=c%")},
+				},
+				Groups: []FilterGroup{
+					{
+						Op: OrOperator,
+						Filters: []Filter{
+							{Field: "id", Op: EqualsOperator, Value: stringValue("1")},
+							{Field: "id", Op: EqualsOperator, Value: stringValue("2")},
+						},
+					},
+				},
+			},
+			OrderBy: []OrderClause{
+				{Field: "id", Direction: OrderDesc},
+			},
+			Limit:  utility.Ptr(10),
+			Offset: utility.Ptr(20),
+		}
+
+		qs, err := EncodeSearchRequest(expected, "")
+		assert.NilError(t, err)
+
+		values, err := url.ParseQuery(qs)
+		assert.NilError(t, err)
+
+		var parsed *SearchRequest
+		err = json.Unmarshal([]byte(values.Get("q")), &parsed)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, parsed, expected)
+	})
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Parse() should not validate the search request", func(t *testing.T) {
+		raw := `{"groups":{"op":"xor","filters":[{"field":"not_allowed","op":"regex","value":"a"}]},"limit":-1}`
+
+		s, err := Parse(raw)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, s, &SearchRequest{
+			Groups: &FilterGroup{
+				Op: LogicalOperator("xor"),
+				Filters: []Filter{
+					{Field: "not_allowed", Op: RelationalOperator("regex"), Value: stringValue("a")},
+				},
+			},
+			Limit: utility.Ptr(-1),
+		})
+
+		err = validateSearchRequest(s, &config{})
+		assert.ErrorContains(t, err, "limit must be null or >= 0")
+	})
+
+	t.Run("Parse() should distinguish null from empty string values", func(t *testing.T) {
+		raw := `{"groups":{"op":"and","filters":[` +
+			`{"field":"deleted_at","op":"eq","value":null},` +
+			`{"field":"name","op":"eq","value":""},` +
+			`{"field":"email","op":"eq"}]}}`
+
+		s, err := Parse(raw)
+		assert.NilError(t, err)
+		assert.Assert(t, s.Groups.Filters[0].IsNull())
+		assert.Assert(t, !s.Groups.Filters[1].IsNull())
+		assert.Equal(t, string(s.Groups.Filters[1].Value), `""`)
+		assert.Assert(t, !s.Groups.Filters[2].IsNull())
+
+		opts := &config{
+			allowedLogicalOperators:    map[LogicalOperator]struct{}{AndOperator: {}},
+			allowedRelationalOperators: map[RelationalOperator]struct{}{EqualsOperator: {}},
+			allowedFilterFields:        map[string]struct{}{"deleted_at": {}, "name": {}, "email": {}},
+		}
+		err = validateSearchRequest(&SearchRequest{Groups: &FilterGroup{Op: AndOperator, Filters: s.Groups.Filters[:2]}}, opts)
+		assert.NilError(t, err)
+		err = validateSearchRequest(s, opts)
+		assert.ErrorContains(t, err, `missing value for field "email"`)
+
+		b, err := json.Marshal(s.Groups.Filters[0])
+		assert.NilError(t, err)
+		assert.Equal(t, string(b), `{"field":"deleted_at","op":"eq","value":null}`)
+	})
+
+	t.Run("Parse() should preserve value types", func(t *testing.T) {
+		raw := `{"groups":{"op":"and","filters":[` +
+			`{"field":"age","op":"gte","value":30},` +
+			`{"field":"id","op":"in","value":[1,"2",3.5]}]}}`
+
+		s, err := Parse(raw)
+		assert.NilError(t, err)
+		assert.Equal(t, string(s.Groups.Filters[0].Value), `30`)
+		assert.Equal(t, string(s.Groups.Filters[1].Value), `[1,"2",3.5]`)
+
+		values, err := s.Groups.Filters[1].values()
+		assert.NilError(t, err)
+		assert.DeepEqual(t, values, []any{int64(1), "2", 3.5})
+	})
+
+	t.Run("Parse() should accept top-level filters as an implicit and group", func(t *testing.T) {
+		shorthand, err := Parse(`{"filters":[{"field":"name","op":"eq","value":"Alice"},{"field":"age","op":"gte","value":30}],"limit":10}`)
+		assert.NilError(t, err)
+
+		explicit, err := Parse(`{"groups":{"op":"and","filters":[{"field":"name","op":"eq","value":"Alice"},{"field":"age","op":"gte","value":30}]},"limit":10}`)
+		assert.NilError(t, err)
+
+		assert.DeepEqual(t, shorthand, explicit)
+
+		opts := &config{
+			allowedLogicalOperators:    map[LogicalOperator]struct{}{AndOperator: {}},
+			allowedRelationalOperators: map[RelationalOperator]struct{}{EqualsOperator: {}, GreaterThanEqualsOperator: {}},
+			allowedFilterFields:        map[string]struct{}{"name": {}, "age": {}},
+		}
+		assert.NilError(t, validateSearchRequest(shorthand, opts))
+
+		delete(opts.allowedFilterFields, "age")
+		assert.Error(t, validateSearchRequest(shorthand, opts), validateSearchRequest(explicit, opts).Error())
+	})
+
+	t.Run("Parse() should combine top-level filters with the root group", func(t *testing.T) {
+		s, err := Parse(`{"groups":{"op":"or","filters":[{"field":"role","op":"eq","value":"admin"}]},"filters":[{"field":"active","op":"eq","value":true}]}`)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, s.Groups, &FilterGroup{
+			Op: AndOperator,
+			Groups: []FilterGroup{
+				{Op: OrOperator, Filters: []Filter{{Field: "role", Op: EqualsOperator, Value: stringValue("admin")}}},
+				{Op: AndOperator, Filters: []Filter{{Field: "active", Op: EqualsOperator, Value: json.RawMessage(`true`)}}},
+			},
+		})
+	})
+
+	t.Run("Parse() should fail due to unknown fields", func(t *testing.T) {
+		_, err := Parse(`{"unknown":true}`)
+		assert.ErrorContains(t, err, `unknown field "unknown"`)
+	})
+
+	t.Run("Parse() should fail due to invalid JSON", func(t *testing.T) {
+		_, err := Parse(`{notvalidJSON}`)
+		assert.ErrorContains(t, err, "invalid character")
+	})
+}
+
+func TestGetSearchConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GetSearchConfig() should return nil outside of a search handler", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		assert.Assert(t, GetSearchConfig(req) == nil)
+	})
+
+	t.Run("GetSearchConfig() should return the handler config downstream", func(t *testing.T) {
+		var got *SearchConfig
+		handler := NewSearchHandler(
+			WithSearchMandatory(false),
+			WithQueryParam("search"),
+			WithLimit(5),
+			WithMaxWindow(100),
+		)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			got = GetSearchConfig(r)
+		}))
+
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/schema", nil))
+
+		assert.Assert(t, got != nil)
+		assert.Equal(t, got.QueryParam, "search")
+		assert.Equal(t, got.SearchMandatory, false)
+		assert.DeepEqual(t, got.Limit, utility.Ptr(5))
+		assert.DeepEqual(t, got.MaxWindow, utility.Ptr(100))
+	})
+
+	t.Run("view() should snapshot the config", func(t *testing.T) {
+		c := &config{
+			queryParam:                 "q",
+			isSearchMandatory:          true,
+			allowedLogicalOperators:    map[LogicalOperator]struct{}{OrOperator: {}, AndOperator: {}},
+			allowedRelationalOperators: map[RelationalOperator]struct{}{NotEqualsOperator: {}, EqualsOperator: {}},
+			allowedFilterFields:        map[string]struct{}{"name": {}, "id": {}},
+			allowedOrderFields:         map[string]struct{}{"id": {}},
+			fieldEnums:                 map[string]map[string]struct{}{"status": {"b": {}, "a": {}}},
+			limit:                      utility.Ptr(10),
+		}
+
+		v := c.view()
+		*c.limit = 20
+		c.allowedFilterFields["email"] = struct{}{}
+
+		assert.DeepEqual(t, v, &SearchConfig{
+			QueryParam:          "q",
+			SearchMandatory:     true,
+			LogicalOperators:    []LogicalOperator{AndOperator, OrOperator},
+			RelationalOperators: []RelationalOperator{EqualsOperator, NotEqualsOperator},
+			FilterFields:        []string{"id", "name"},
+			OrderFields:         []string{"id"},
+			FieldEnums:          map[string][]string{"status": {"a", "b"}},
+			Limit:               utility.Ptr(10),
+		})
+	})
+}
@@ -0,0 +1,218 @@
+package qparams
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// sqlBuilder renders a SearchRequest into SQL. It writes the parameterized
+// query and an interpolated copy side by side, so that values containing
+// placeholder characters never corrupt the display form.
+type sqlBuilder struct {
+	query        strings.Builder
+	interpolated strings.Builder
+	comments     bool
+}
+
+// SQLOption is a functional option used to configure the SQL rendering
+// performed by ExplainSQL.
+type SQLOption func(*sqlBuilder)
+
+// WithSQLComments configures whether each filter group is prefixed by an inline
+// comment stating its logical operator (e.g. "/* group: and */"), which makes
+// nested conditions easier to read in slow-query logs. Comments never contain
+// values, so they do not affect parameterization. Default is false.
+func WithSQLComments(enabled bool) SQLOption {
+	return func(b *sqlBuilder) {
+		b.comments = enabled
+	}
+}
+
+// ExplainSQL returns the SQL clauses (where, order by, limit and offset)
+// that the given SearchRequest would produce, without executing anything.
+// The first line holds the parameterized SQL with "?" placeholders, followed
+// by a commented version where values are inlined.
+//
+// The interpolated version is meant for debugging purposes only: values are
+// quoted naively and it must NEVER be executed against a database.
+//
+// Example:
+//
+//	fmt.Println(qparams.ExplainSQL(s))
+//	// where status = ? and (role = ? or role = ?) limit 20
+//	// -- UNSAFE, for display only: where status = 'active' and (role = 'admin' or role = 'editor') limit 20
+func ExplainSQL(s *SearchRequest, opts ...SQLOption) string {
+	b := &sqlBuilder{}
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	b.writeSearchRequest(s)
+
+	return b.query.String() + "\n-- UNSAFE, for display only: " + b.interpolated.String()
+}
+
+func (b *sqlBuilder) writeSearchRequest(s *SearchRequest) {
+	if s == nil {
+		return
+	}
+
+	if s.Groups != nil && !isEmptyGroup(s.Groups) {
+		b.writeKeyword("where ")
+		b.writeGroup(s.Groups)
+	}
+
+	if len(s.OrderBy) > 0 {
+		b.writeSeparator()
+		b.writeKeyword("order by ")
+		for i, o := range s.OrderBy {
+			if i > 0 {
+				b.writeKeyword(", ")
+			}
+			b.writeKeyword(o.Field + " " + o.Direction.Symbol())
+		}
+	}
+
+	if s.Limit != nil {
+		b.writeSeparator()
+		b.writeKeyword("limit " + strconv.Itoa(*s.Limit))
+	}
+
+	if s.Offset != nil {
+		b.writeSeparator()
+		b.writeKeyword("offset " + strconv.Itoa(*s.Offset))
+	}
+}
+
+func (b *sqlBuilder) writeGroup(g *FilterGroup) {
+	sep := " " + g.Op.Symbol() + " "
+	first := true
+
+	if b.comments {
+		b.writeKeyword("/* group: " + g.Op.Symbol() + " */ ")
+	}
+
+	for _, f := range g.Filters {
+		if !first {
+			b.writeKeyword(sep)
+		}
+		first = false
+		b.writeFilter(f)
+	}
+
+	for _, sg := range g.Groups {
+		if isEmptyGroup(&sg) {
+			continue
+		}
+
+		if !first {
+			b.writeKeyword(sep)
+		}
+		first = false
+		b.writeKeyword("(")
+		b.writeGroup(&sg)
+		b.writeKeyword(")")
+	}
+}
+
+func (b *sqlBuilder) writeFilter(f Filter) {
+	if f.IsNull() {
+		if f.Op == NotEqualsOperator {
+			b.writeKeyword(f.Field + " is not null")
+		} else {
+			b.writeKeyword(f.Field + " is null")
+		}
+		return
+	}
+
+	b.writeKeyword(f.Field + " " + f.Op.Symbol() + " ")
+
+	values := filterArgs(f)
+	if f.Op == BetweenOperator && len(values) == 2 {
+		b.writeValue(values[0])
+		b.writeKeyword(" and ")
+		b.writeValue(values[1])
+		return
+	}
+
+	if f.Op != InOperator && f.Op != BetweenOperator && len(values) == 1 {
+		b.writeValue(values[0])
+		return
+	}
+
+	b.writeKeyword("(")
+	for i, v := range values {
+		if i > 0 {
+			b.writeKeyword(", ")
+		}
+		b.writeValue(v)
+	}
+	b.writeKeyword(")")
+}
+
+// filterArgs returns the typed query arguments of f. A string value used with
+// the in or between operators is split on commas. Invalid values, which validation rejects,
+// yield no argument, so that their raw JSON text is never bound.
+func filterArgs(f Filter) []any {
+	if f.Op == InOperator || f.Op == BetweenOperator {
+		if v := bytes.TrimSpace(f.Value); len(v) > 0 && v[0] == '"' {
+			parts, err := f.AsStringSlice()
+			if err == nil {
+				args := make([]any, len(parts))
+				for i, p := range parts {
+					args[i] = p
+				}
+				return args
+			}
+		}
+	}
+
+	values, err := f.values()
+	if err != nil {
+		return nil
+	}
+
+	return values
+}
+
+// writeKeyword writes trusted SQL text to both the query and its interpolated form.
+func (b *sqlBuilder) writeKeyword(s string) {
+	b.query.WriteString(s)
+	b.interpolated.WriteString(s)
+}
+
+// writeValue writes a placeholder for v and inlines it in the interpolated form.
+// Strings are quoted, other values are written as is.
+func (b *sqlBuilder) writeValue(v any) {
+	b.query.WriteString("?")
+
+	if s, ok := v.(string); ok {
+		b.interpolated.WriteString("'" + strings.ReplaceAll(s, "'", "''") + "'")
+		return
+	}
+	b.interpolated.WriteString(fmt.Sprint(v))
+}
+
+func (b *sqlBuilder) writeSeparator() {
+	if b.query.Len() > 0 {
+		b.writeKeyword(" ")
+	}
+}
+
+// isEmptyGroup reports whether g produces no condition at all,
+// either because it has no filters or because all its nested groups are empty.
+func isEmptyGroup(g *FilterGroup) bool {
+	if len(g.Filters) > 0 {
+		return false
+	}
+
+	for _, sg := range g.Groups {
+		if !isEmptyGroup(&sg) {
+			return false
+		}
+	}
+
+	return true
+}
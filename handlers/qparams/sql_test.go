@@ -0,0 +1,186 @@
+package qparams
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/paccolamano/golazy/utility"
+	"gotest.tools/v3/assert"
+)
+
+func TestExplainSQL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		search   *SearchRequest
+		expected string
+	}{
+		{
+			name:     "with nil search request",
+			search:   nil,
+			expected: "\n-- UNSAFE, for display only: ",
+		},
+		{
+			name: "with nested groups",
+			search: &SearchRequest{
+				Groups: &FilterGroup{
+					Op: AndOperator,
+					Filters: []Filter{
+						{Field: "status", Op: EqualsOperator, Value: stringValue("active")},
+					},
+					Groups: []FilterGroup{
+						{
+							Op: OrOperator,
+							Filters: []Filter{
+								{Field: "role", Op: EqualsOperator, Value: stringValue("admin")},
+								{Field: "name", Op: ILikeOperator, Value: stringValue("o'brien")},
+							},
+						},
+						{Op: AndOperator},
+					},
+				},
+				OrderBy: []OrderClause{
+					{Field: "created_at", Direction: OrderDesc},
+					{Field: "id", Direction: OrderAsc},
+				},
+				Limit:  utility.Ptr(20),
+				Offset: utility.Ptr(40),
+			},
+			expected: "where status = ? and (role = ? or name ilike ?) order by created_at desc, id asc limit 20 offset 40\n" +
+				"-- UNSAFE, for display only: where status = 'active' and (role = 'admin' or name ilike 'o''brien') order by created_at desc, id asc limit 20 offset 40",
+		},
+		{
+			name: "with in operator",
+			search: &SearchRequest{
+				Groups: &FilterGroup{
+					Op: AndOperator,
+					Filters: []Filter{
+						{Field: "id", Op: InOperator, Value: stringValue("1, 2,3")},
+					},
+				},
+			},
+			expected: "where id in (?, ?, ?)\n" +
+				"-- UNSAFE, for display only: where id in ('1', '2', '3')",
+		},
+		{
+			name: "with typed values",
+			search: &SearchRequest{
+				Groups: &FilterGroup{
+					Op: AndOperator,
+					Filters: []Filter{
+						{Field: "age", Op: GreaterThanEqualsOperator, Value: json.RawMessage(`30`)},
+						{Field: "active", Op: EqualsOperator, Value: json.RawMessage(`true`)},
+						{Field: "id", Op: InOperator, Value: json.RawMessage(`[1, "2", 3.5]`)},
+					},
+				},
+			},
+			expected: "where age >= ? and active = ? and id in (?, ?, ?)\n" +
+				"-- UNSAFE, for display only: where age >= 30 and active = true and id in (1, '2', 3.5)",
+		},
+		{
+			name: "with between operator",
+			search: &SearchRequest{
+				Groups: &FilterGroup{
+					Op: AndOperator,
+					Filters: []Filter{
+						{Field: "price", Op: BetweenOperator, Value: json.RawMessage(`[10, 20]`)},
+						{Field: "created_at", Op: BetweenOperator, Value: stringValue("2025-01-01, 2025-12-31")},
+					},
+				},
+			},
+			expected: "where price between ? and ? and created_at between ? and ?\n" +
+				"-- UNSAFE, for display only: where price between 10 and 20 and created_at between '2025-01-01' and '2025-12-31'",
+		},
+		{
+			name: "with empty in list",
+			search: &SearchRequest{
+				Groups: &FilterGroup{
+					Op: AndOperator,
+					Filters: []Filter{
+						{Field: "id", Op: InOperator, Value: json.RawMessage(`[]`)},
+						{Field: "name", Op: EqualsOperator, Value: json.RawMessage(`{"a":1}`)},
+					},
+				},
+			},
+			expected: "where id in () and name = ()\n" +
+				"-- UNSAFE, for display only: where id in () and name = ()",
+		},
+		{
+			name: "with null values",
+			search: &SearchRequest{
+				Groups: &FilterGroup{
+					Op: AndOperator,
+					Filters: []Filter{
+						{Field: "deleted_at", Op: EqualsOperator, Value: json.RawMessage(`null`)},
+						{Field: "updated_at", Op: NotEqualsOperator, Value: json.RawMessage(`null`)},
+						{Field: "name", Op: EqualsOperator, Value: stringValue("")},
+					},
+				},
+			},
+			expected: "where deleted_at is null and updated_at is not null and name = ?\n" +
+				"-- UNSAFE, for display only: where deleted_at is null and updated_at is not null and name = ''",
+		},
+		{
+			name: "with placeholder in value",
+			search: &SearchRequest{
+				Groups: &FilterGroup{
+					Op: AndOperator,
+					Filters: []Filter{
+						{Field: "name", Op: EqualsOperator, Value: stringValue("?")},
+					},
+				},
+			},
+			expected: "where name = ?\n" +
+				"-- UNSAFE, for display only: where name = '?'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := ExplainSQL(tt.search)
+			assert.Equal(t, res, tt.expected)
+			assert.Equal(t, strings.Count(res, "\n"), 1)
+		})
+	}
+}
+
+func TestExplainSQLWithComments(t *testing.T) {
+	t.Parallel()
+
+	s := &SearchRequest{
+		Groups: &FilterGroup{
+			Op: AndOperator,
+			Filters: []Filter{
+				{Field: "status", Op: EqualsOperator, Value: stringValue("active")},
+			},
+			Groups: []FilterGroup{
+				{
+					Op: OrOperator,
+					Filters: []Filter{
+						{Field: "role", Op: EqualsOperator, Value: stringValue("admin")},
+						{Field: "role", Op: EqualsOperator, Value: stringValue("*/ drop")},
+					},
+				},
+			},
+		},
+	}
+
+	t.Run("should not emit comments by default", func(t *testing.T) {
+		assert.Assert(t, !strings.Contains(ExplainSQL(s), "/*"))
+		assert.Equal(t, ExplainSQL(s), ExplainSQL(s, WithSQLComments(false)))
+	})
+
+	t.Run("should emit a comment per group when enabled", func(t *testing.T) {
+		b := &sqlBuilder{}
+		WithSQLComments(true)(b)
+		b.writeSearchRequest(s)
+
+		assert.Equal(t, b.query.String(),
+			"where /* group: and */ status = ? and (/* group: or */ role = ? or role = ?)")
+		assert.Equal(t, ExplainSQL(s, WithSQLComments(true)),
+			"where /* group: and */ status = ? and (/* group: or */ role = ? or role = ?)\n"+
+				"-- UNSAFE, for display only: where /* group: and */ status = 'active' and (/* group: or */ role = 'admin' or role = '*/ drop')")
+	})
+}
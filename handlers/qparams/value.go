@@ -0,0 +1,271 @@
+package qparams
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IsNull reports whether the filter value is a JSON null. A missing value is
+// not null.
+func (f Filter) IsNull() bool {
+	return bytes.Equal(bytes.TrimSpace(f.Value), []byte("null"))
+}
+
+// isMissing reports whether the filter has no value at all.
+func (f Filter) isMissing() bool {
+	return len(bytes.TrimSpace(f.Value)) == 0
+}
+
+// AsString returns the filter value as a string. Numbers and booleans are
+// returned in their JSON form (e.g. "30", "true"). It fails for null, arrays
+// and objects.
+func (f Filter) AsString() (string, error) {
+	v, err := decodeScalar(f.Value)
+	if err != nil {
+		return "", err
+	}
+
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	default:
+		return "", fmt.Errorf("value of field %q is null", f.Field)
+	}
+}
+
+// AsInt returns the filter value as an int. Both JSON integers and strings
+// holding an integer (e.g. "30") are accepted.
+func (f Filter) AsInt() (int, error) {
+	s, err := f.AsString()
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("value of field %q is not an integer: %w", f.Field, err)
+	}
+
+	return n, nil
+}
+
+// AsStringSlice returns the filter value as a slice of strings, as used by the
+// in and between operators. A JSON array yields one element per item, converted like
+// AsString does. For backward compatibility, a JSON string is split on commas
+// and each element is trimmed (e.g. "1, 2,3").
+func (f Filter) AsStringSlice() ([]string, error) {
+	v := bytes.TrimSpace(f.Value)
+	if len(v) == 0 || v[0] != '[' {
+		s, err := f.AsString()
+		if err != nil {
+			return nil, err
+		}
+
+		parts := strings.Split(s, ",")
+		for i := range parts {
+			parts[i] = strings.TrimSpace(parts[i])
+		}
+		return parts, nil
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(v, &items); err != nil {
+		return nil, fmt.Errorf("value of field %q is not a valid array: %w", f.Field, err)
+	}
+
+	result := make([]string, len(items))
+	for i, item := range items {
+		s, err := Filter{Field: f.Field, Value: item}.AsString()
+		if err != nil {
+			return nil, err
+		}
+		result[i] = s
+	}
+
+	return result, nil
+}
+
+// values returns the filter value decoded with its JSON type, as a single
+// element for scalar values or one element per item for arrays. Strings are
+// returned as string, numbers as int64 or float64 and booleans as bool.
+func (f Filter) values() ([]any, error) {
+	v := bytes.TrimSpace(f.Value)
+	if len(v) > 0 && v[0] == '[' {
+		var items []json.RawMessage
+		if err := json.Unmarshal(v, &items); err != nil {
+			return nil, err
+		}
+
+		result := make([]any, len(items))
+		for i, item := range items {
+			value, err := decodeScalar(item)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = typedValue(value)
+		}
+		return result, nil
+	}
+
+	value, err := decodeScalar(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return []any{typedValue(value)}, nil
+}
+
+// validateValue checks that the value of f is consistent with its operator.
+func validateValue(f Filter) error {
+	if f.isMissing() {
+		return fmt.Errorf("missing value for field %q", f.Field)
+	}
+
+	if f.IsNull() {
+		if f.Op != EqualsOperator && f.Op != NotEqualsOperator {
+			return fmt.Errorf("relational operator %q does not support null value for field %q", f.Op, f.Field)
+		}
+		return nil
+	}
+
+	v := bytes.TrimSpace(f.Value)
+	if v[0] == '[' && f.Op != InOperator && f.Op != BetweenOperator {
+		return fmt.Errorf("relational operator %q does not support array value for field %q", f.Op, f.Field)
+	}
+
+	if _, err := f.values(); err != nil {
+		return fmt.Errorf("invalid value for field %q: %w", f.Field, err)
+	}
+
+	switch f.Op {
+	case InOperator:
+		return validateElements(f)
+	case BetweenOperator:
+		return validateBounds(f)
+	}
+
+	return nil
+}
+
+// validateElements checks that the value of an in filter holds at least one
+// element and no empty one.
+func validateElements(f Filter) error {
+	elements, err := f.AsStringSlice()
+	if err != nil {
+		return fmt.Errorf("invalid value for field %q: %w", f.Field, err)
+	}
+
+	if len(elements) == 0 || slices.Contains(elements, "") {
+		return fmt.Errorf("relational operator %q requires non-empty values for field %q", f.Op, f.Field)
+	}
+
+	return nil
+}
+
+// validateBounds checks that the value of a between filter holds exactly two
+// bounds and, when both are numbers or both are times (RFC 3339 timestamps or
+// dates), that the lower one is not greater than the upper one. Other bounds
+// are compared by the database and are not checked.
+func validateBounds(f Filter) error {
+	bounds, err := f.AsStringSlice()
+	if err != nil {
+		return fmt.Errorf("invalid value for field %q: %w", f.Field, err)
+	}
+
+	if len(bounds) != 2 || bounds[0] == "" || bounds[1] == "" {
+		return fmt.Errorf("relational operator %q requires exactly two bounds for field %q", f.Op, f.Field)
+	}
+
+	if compareBounds(bounds[0], bounds[1]) > 0 {
+		return fmt.Errorf("lower bound %q is greater than upper bound %q for field %q", bounds[0], bounds[1], f.Field)
+	}
+
+	return nil
+}
+
+// compareBounds compares lower and upper as numbers or as times, returning
+// 0 when they are of neither kind.
+func compareBounds(lower, upper string) int {
+	l, errL := strconv.ParseFloat(lower, 64)
+	u, errU := strconv.ParseFloat(upper, 64)
+	if errL == nil && errU == nil {
+		return cmp.Compare(l, u)
+	}
+
+	lt, okL := parseTime(lower)
+	ut, okU := parseTime(upper)
+	if okL && okU {
+		return lt.Compare(ut)
+	}
+
+	return 0
+}
+
+// parseTime parses s as an RFC 3339 timestamp or as a date.
+func parseTime(s string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339Nano, time.DateOnly} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// decodeScalar decodes a JSON string, number, boolean or null. Numbers are
+// returned as json.Number and null as nil.
+func decodeScalar(raw json.RawMessage) (any, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return nil, errors.New("value is missing")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var v any
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	switch v.(type) {
+	case nil, string, json.Number, bool:
+		return v, nil
+	default:
+		return nil, errors.New("value must be a string, number, boolean or null")
+	}
+}
+
+// typedValue converts json.Number to int64 when possible, float64 otherwise.
+func typedValue(v any) any {
+	n, ok := v.(json.Number)
+	if !ok {
+		return v
+	}
+
+	if i, err := n.Int64(); err == nil {
+		return i
+	}
+
+	if f, err := n.Float64(); err == nil {
+		return f
+	}
+
+	return n.String()
+}
+
+// stringValue returns the JSON encoding of s.
+func stringValue(s string) json.RawMessage {
+	b, _ := json.Marshal(s)
+	return b
+}
@@ -0,0 +1,172 @@
+package qparams
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestFilterAccessors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("AsString() should convert scalar values", func(t *testing.T) {
+		for raw, expected := range map[string]string{
+			`"Alice"`: "Alice",
+			`30`:      "30",
+			`2.5`:     "2.5",
+			`true`:    "true",
+		} {
+			s, err := Filter{Value: json.RawMessage(raw)}.AsString()
+			assert.NilError(t, err)
+			assert.Equal(t, s, expected)
+		}
+	})
+
+	t.Run("AsString() should fail for null and arrays", func(t *testing.T) {
+		_, err := Filter{Field: "name", Value: json.RawMessage(`null`)}.AsString()
+		assert.ErrorContains(t, err, `value of field "name" is null`)
+
+		_, err = Filter{Field: "name"}.AsString()
+		assert.ErrorContains(t, err, "value is missing")
+
+		_, err = Filter{Field: "name", Value: json.RawMessage(`[1]`)}.AsString()
+		assert.ErrorContains(t, err, "value must be a string, number, boolean or null")
+	})
+
+	t.Run("AsInt() should accept numbers and numeric strings", func(t *testing.T) {
+		n, err := Filter{Value: json.RawMessage(`42`)}.AsInt()
+		assert.NilError(t, err)
+		assert.Equal(t, n, 42)
+
+		n, err = Filter{Value: json.RawMessage(`"42"`)}.AsInt()
+		assert.NilError(t, err)
+		assert.Equal(t, n, 42)
+
+		_, err = Filter{Field: "age", Value: json.RawMessage(`4.2`)}.AsInt()
+		assert.ErrorContains(t, err, `value of field "age" is not an integer`)
+	})
+
+	t.Run("AsStringSlice() should handle arrays and comma separated strings", func(t *testing.T) {
+		values, err := Filter{Value: json.RawMessage(`[1, "two", true]`)}.AsStringSlice()
+		assert.NilError(t, err)
+		assert.DeepEqual(t, values, []string{"1", "two", "true"})
+
+		values, err = Filter{Value: json.RawMessage(`"1, 2,3"`)}.AsStringSlice()
+		assert.NilError(t, err)
+		assert.DeepEqual(t, values, []string{"1", "2", "3"})
+
+		_, err = Filter{Value: json.RawMessage(`[[1]]`)}.AsStringSlice()
+		assert.ErrorContains(t, err, "value must be a string, number, boolean or null")
+	})
+}
+
+func TestValidateValue(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		filter      Filter
+		expectedErr string
+	}{
+		{
+			name:   "with string value",
+			filter: Filter{Field: "name", Op: EqualsOperator, Value: json.RawMessage(`"Alice"`)},
+		},
+		{
+			name:   "with array value and in operator",
+			filter: Filter{Field: "id", Op: InOperator, Value: json.RawMessage(`[1, 2]`)},
+		},
+		{
+			name:        "with empty array value and in operator",
+			filter:      Filter{Field: "id", Op: InOperator, Value: json.RawMessage(`[]`)},
+			expectedErr: `relational operator "in" requires non-empty values for field "id"`,
+		},
+		{
+			name:        "with empty string value and in operator",
+			filter:      Filter{Field: "id", Op: InOperator, Value: json.RawMessage(`""`)},
+			expectedErr: `relational operator "in" requires non-empty values for field "id"`,
+		},
+		{
+			name:        "with an empty element and in operator",
+			filter:      Filter{Field: "id", Op: InOperator, Value: json.RawMessage(`"1,,2"`)},
+			expectedErr: `relational operator "in" requires non-empty values for field "id"`,
+		},
+		{
+			name:        "with array value and eq operator",
+			filter:      Filter{Field: "id", Op: EqualsOperator, Value: json.RawMessage(`[1, 2]`)},
+			expectedErr: `relational operator "eq" does not support array value for field "id"`,
+		},
+		{
+			name:        "with object value",
+			filter:      Filter{Field: "id", Op: EqualsOperator, Value: json.RawMessage(`{"a":1}`)},
+			expectedErr: `invalid value for field "id"`,
+		},
+		{
+			name:   "with null value and eq operator",
+			filter: Filter{Field: "id", Op: EqualsOperator, Value: json.RawMessage(`null`)},
+		},
+		{
+			name:        "with missing value",
+			filter:      Filter{Field: "id", Op: EqualsOperator},
+			expectedErr: `missing value for field "id"`,
+		},
+		{
+			name:        "with null value and lt operator",
+			filter:      Filter{Field: "id", Op: LowerThanOperator, Value: json.RawMessage(`null`)},
+			expectedErr: `relational operator "lt" does not support null value for field "id"`,
+		},
+		{
+			name:   "with numeric bounds",
+			filter: Filter{Field: "price", Op: BetweenOperator, Value: json.RawMessage(`[5, 10.5]`)},
+		},
+		{
+			name:   "with equal bounds",
+			filter: Filter{Field: "price", Op: BetweenOperator, Value: json.RawMessage(`"10,10"`)},
+		},
+		{
+			name:   "with time bounds",
+			filter: Filter{Field: "created_at", Op: BetweenOperator, Value: json.RawMessage(`["2025-01-01", "2025-01-01T12:00:00Z"]`)},
+		},
+		{
+			name:   "with text bounds",
+			filter: Filter{Field: "name", Op: BetweenOperator, Value: json.RawMessage(`["m", "a"]`)},
+		},
+		{
+			name:        "with inverted numeric bounds",
+			filter:      Filter{Field: "price", Op: BetweenOperator, Value: json.RawMessage(`"10,5"`)},
+			expectedErr: `lower bound "10" is greater than upper bound "5" for field "price"`,
+		},
+		{
+			name:        "with inverted negative bounds",
+			filter:      Filter{Field: "temp", Op: BetweenOperator, Value: json.RawMessage(`[-1, -5]`)},
+			expectedErr: `lower bound "-1" is greater than upper bound "-5" for field "temp"`,
+		},
+		{
+			name:        "with inverted time bounds",
+			filter:      Filter{Field: "created_at", Op: BetweenOperator, Value: json.RawMessage(`"2025-02-01,2025-01-01T00:00:00Z"`)},
+			expectedErr: `lower bound "2025-02-01" is greater than upper bound "2025-01-01T00:00:00Z" for field "created_at"`,
+		},
+		{
+			name:        "with a single bound",
+			filter:      Filter{Field: "price", Op: BetweenOperator, Value: json.RawMessage(`[5]`)},
+			expectedErr: `relational operator "between" requires exactly two bounds for field "price"`,
+		},
+		{
+			name:        "with an empty bound",
+			filter:      Filter{Field: "price", Op: BetweenOperator, Value: json.RawMessage(`"5,"`)},
+			expectedErr: `relational operator "between" requires exactly two bounds for field "price"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateValue(tt.filter)
+			if tt.expectedErr == "" {
+				assert.NilError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.expectedErr)
+		})
+	}
+}
@@ -46,12 +46,39 @@
 package recover
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"log/slog"
 	"net/http"
-	"runtime/debug"
+	"os"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/paccolamano/golazy/handlers/tracer"
+)
+
+// defaultStackBufferSize is the default size of the buffers used to capture stack traces.
+const defaultStackBufferSize = 64 << 10
+
+// RequestField represents a request attribute that can be attached to the
+// recover log entry.
+type RequestField string
+
+const (
+	// RequestFieldMethod logs the HTTP request method (GET, POST, etc).
+	RequestFieldMethod RequestField = "method"
+	// RequestFieldPath logs the request URL path.
+	RequestFieldPath RequestField = "path"
+	// RequestFieldQuery logs the raw query string from the URL.
+	RequestFieldQuery RequestField = "query"
+	// RequestFieldTraceID logs the trace ID stored by the tracer middleware
+	// under its default context key, if any.
+	RequestFieldTraceID RequestField = "traceID"
 )
 
 // Logger is a minimal structured-logger interface used by New.
@@ -61,6 +88,42 @@ type Logger interface {
 	LogAttrs(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr)
 }
 
+// Discard is a Logger that discards all log messages. It is useful to
+// silence the recover handler, e.g. in tests.
+var Discard Logger = noopLogger{}
+
+// noopLogger is a Logger that does nothing.
+type noopLogger struct{}
+
+// LogAttrs for noopLogger does nothing.
+func (noopLogger) LogAttrs(_ context.Context, _ slog.Level, _ string, _ ...slog.Attr) {}
+
+// ErrorHandler defines the signature of a function responsible for handling
+// a recovered panic converted into an error. It receives the HTTP response
+// writer, the request, and a *PanicError.
+type ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+
+// PanicError is the error passed to the ErrorHandler set with WithErrorHandler.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+
+	// Stack is the stack trace, nil unless IncludeStack is set.
+	Stack []byte
+}
+
+// Error returns a message describing the recovered value.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the recovered value if it is an error, so that errors.Is
+// and errors.As see through a panic(err).
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
 // config holds configuration for the recover handler.
 type config struct {
 	// Logger is used for structured logging. Defaults to slog.Default().
@@ -75,6 +138,18 @@ type config struct {
 	// so the default is false.
 	IncludeStack bool
 
+	// StackBufferSize is the size in bytes of the buffers used to capture the
+	// stack trace. Buffers are pooled and reused across panics; stacks longer
+	// than the buffer are truncated. Defaults to 64 KiB; values lower than or
+	// equal to zero keep the default.
+	StackBufferSize int
+
+	// StackDepth, when positive, limits the captured stack trace to the top
+	// StackDepth frames below the panic, excluding the recover internals. The
+	// formatted frames are still truncated to StackBufferSize bytes.
+	// Defaults to 0, which captures the full stack of the goroutine.
+	StackDepth int
+
 	// Message is the log message used when logging recovered panics.
 	// Defaults to "recovered from panic".
 	Message string
@@ -83,10 +158,41 @@ type config struct {
 	// Defaults to http.StatusInternalServerError (500).
 	StatusCode int
 
+	// ResponseMarshaler, when set, builds the body and content type written by
+	// the default callback, replacing the default JSON body. It receives the
+	// configured StatusCode and the recovered value.
+	ResponseMarshaler func(status int, recovered any) ([]byte, string)
+
 	// Callback is invoked after a panic is recovered. It receives the ResponseWriter,
 	// the Request, the recovered value (any), and the stack trace (which may be nil).
 	// If nil, a default JSON 500 response is written.
 	Callback func(w http.ResponseWriter, r *http.Request, recovered any, stack []byte)
+
+	// ErrorHandler, when set, replaces Callback: the recovered panic is
+	// converted into a *PanicError and handed to it.
+	ErrorHandler ErrorHandler
+
+	// Callbacks are additional callbacks invoked after Callback, in registration
+	// order. Only the first callback that writes a response is authoritative.
+	Callbacks []func(w http.ResponseWriter, r *http.Request, recovered any, stack []byte)
+
+	// FieldMask lists sensitive keys (e.g. "password", "token") whose values
+	// are redacted from the logged error message. Matching is case-insensitive
+	// and applies to "key=value" and "key:value" pairs. Defaults to none.
+	FieldMask []string
+
+	// RequestFields lists the request attributes logged alongside the
+	// recovered panic. Defaults to none.
+	RequestFields []RequestField
+
+	// Metrics, when set, is invoked with the route pattern of the request
+	// for each recovered panic. Defaults to none.
+	Metrics func(route string)
+
+	// CrashFile, when set, is the path of a file to which an entry with the
+	// full stack trace is appended for each recovered panic, regardless of
+	// Logger, Level and IncludeStack. Defaults to none.
+	CrashFile string
 }
 
 // Option mutates Options.
@@ -113,6 +219,31 @@ func WithIncludeStack(include bool) Option {
 	}
 }
 
+// WithStackDepth limits the captured stack trace to the top frames frames,
+// starting from the function that panicked and skipping the recover
+// middleware's own frames. Each frame is formatted as the function name
+// followed by its file and line. The formatted frames are still truncated to
+// the size set by WithStackBufferSize. It has no effect unless IncludeStack
+// is set.
+func WithStackDepth(frames int) Option {
+	return func(c *config) {
+		c.StackDepth = frames
+	}
+}
+
+// WithStackBufferSize sets the size in bytes of the pooled buffers used to
+// capture the stack trace. The captured stack is truncated to n bytes, which
+// bounds the size of the log entry, also when WithStackDepth is set. Values lower than or equal to zero are
+// ignored and the default of 64 KiB is kept. Callbacks receive a copy of the
+// stack, so they may retain it.
+func WithStackBufferSize(n int) Option {
+	return func(c *config) {
+		if n > 0 {
+			c.StackBufferSize = n
+		}
+	}
+}
+
 // WithMessage sets the log message used when a panic is recovered.
 func WithMessage(msg string) Option {
 	return func(c *config) {
@@ -127,6 +258,16 @@ func WithStatusCode(code int) Option {
 	}
 }
 
+// WithResponseMarshaler sets a function building the body and content type of
+// the response written by the default callback, with the configured status
+// code. It is a lighter alternative to WithCallback to match an application
+// error envelope. It has no effect when a custom callback is set.
+func WithResponseMarshaler(f func(status int, recovered any) ([]byte, string)) Option {
+	return func(c *config) {
+		c.ResponseMarshaler = f
+	}
+}
+
 // WithCallback sets a custom callback invoked after recovery. The callback
 // receives the recovered value and the stack trace (which may be nil if IncludeStack=false).
 func WithCallback(f func(w http.ResponseWriter, r *http.Request, recovered any, stack []byte)) Option {
@@ -135,13 +276,83 @@ func WithCallback(f func(w http.ResponseWriter, r *http.Request, recovered any,
 	}
 }
 
+// WithErrorHandler sets a handler receiving the recovered panic converted into
+// a *PanicError, e.g. to render it with the application's error handling
+// (such as httputil.WriteHTTPError) rather than the recover package writing
+// the response. It replaces the main callback, whether the default one or the
+// one set by WithCallback; callbacks added with WithAppendCallback still run
+// after it. The panic is logged as usual beforehand.
+func WithErrorHandler(handler ErrorHandler) Option {
+	return func(c *config) {
+		c.ErrorHandler = handler
+	}
+}
+
+// WithAppendCallback registers an additional callback invoked after the main
+// callback (the default one or the one set by WithCallback). Callbacks run in
+// registration order and only the first one that writes a response is
+// authoritative, so e.g. a metrics callback can be composed with a custom
+// response without one overriding the other.
+func WithAppendCallback(f func(w http.ResponseWriter, r *http.Request, recovered any, stack []byte)) Option {
+	return func(c *config) {
+		c.Callbacks = append(c.Callbacks, f)
+	}
+}
+
+// WithFieldMask sets the sensitive keys whose values are replaced with "***"
+// in the logged error message. When it is set, recovered values that are not
+// errors are formatted with %+v, so that struct fields are written as
+// "Key:value". This is best-effort: it only catches values written as
+// "key=value" or "key:value", so e.g. an error message that embeds a struct
+// formatted with %v is not masked; it reduces but does not rule out
+// accidental secret logging.
+func WithFieldMask(keys ...string) Option {
+	return func(c *config) {
+		c.FieldMask = append(c.FieldMask, keys...)
+	}
+}
+
+// WithRequestFields sets the request attributes (method, path, trace ID, ...)
+// logged alongside the recovered panic.
+func WithRequestFields(fields ...RequestField) Option {
+	return func(c *config) {
+		c.RequestFields = fields
+	}
+}
+
+// WithMetrics sets a function invoked once per recovered panic with the route
+// pattern of the request (r.Pattern, e.g. "GET /users/{id}"), so that panic
+// rates can be counted per endpoint. The route is empty when the request was
+// not routed by an http.ServeMux, e.g. when the recover handler wraps the mux
+// and no route matched. It is invoked before the response callbacks.
+func WithMetrics(f func(route string)) Option {
+	return func(c *config) {
+		c.Metrics = f
+	}
+}
+
+// WithCrashFile sets the path of a file to which each recovered panic appends
+// an entry made of a timestamp, the request method and path, the error message
+// and the full stack trace (truncated to the stack buffer size). It is written
+// independently of the logger, the log level and IncludeStack, so that stacks
+// are available for post-mortem debugging even when logging is quiet.
+// The file is created if needed and only ever appended to: rotation is out of
+// scope and must be handled externally (e.g. with logrotate copytruncate).
+// Failures to write the file are logged.
+func WithCrashFile(path string) Option {
+	return func(c *config) {
+		c.CrashFile = path
+	}
+}
+
 // New returns a handler that recovers from panics in handlers.
 //
 // Behavior & defaults:
 //   - structured logging via Logger (defaults to slog.Default()).
 //   - default log level: slog.LevelError.
 //   - by default the stack trace is NOT captured (IncludeStack=false) to avoid overhead.
-//   - default callback writes a JSON 500: {"error":"Internal Server Error"}.
+//   - default callback writes a JSON 500: {"error":"Internal Server Error"},
+//     whose body can be customized with WithResponseMarshaler.
 //
 // Example:
 //
@@ -165,14 +376,27 @@ func WithCallback(f func(w http.ResponseWriter, r *http.Request, recovered any,
 //	)(myHandler))
 func New(opts ...Option) func(http.Handler) http.Handler {
 	c := &config{
-		Logger:       slog.Default(),
-		Level:        slog.LevelError,
-		IncludeStack: false,
-		Message:      "recovered from panic",
-		StatusCode:   http.StatusInternalServerError,
+		Logger:          slog.Default(),
+		Level:           slog.LevelError,
+		IncludeStack:    false,
+		StackBufferSize: defaultStackBufferSize,
+		Message:         "recovered from panic",
+		StatusCode:      http.StatusInternalServerError,
 	}
 
-	c.Callback = func(w http.ResponseWriter, r *http.Request, _ any, _ []byte) {
+	c.Callback = func(w http.ResponseWriter, r *http.Request, recovered any, _ []byte) {
+		if c.ResponseMarshaler != nil {
+			body, contentType := c.ResponseMarshaler(c.StatusCode, recovered)
+			w.Header().Set("Content-Type", contentType)
+			w.WriteHeader(c.StatusCode)
+			if _, err := w.Write(body); err != nil {
+				c.Logger.LogAttrs(r.Context(), c.Level,
+					"failed to send recovery response",
+					slog.String("error", err.Error()))
+			}
+			return
+		}
+
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(c.StatusCode)
 		err := json.NewEncoder(w).Encode(map[string]string{
@@ -189,6 +413,18 @@ func New(opts ...Option) func(http.Handler) http.Handler {
 		opt(c)
 	}
 
+	mask := compileFieldMask(c.FieldMask)
+
+	stackPool := &sync.Pool{
+		New: func() any {
+			buf := make([]byte, c.StackBufferSize)
+			return &buf
+		},
+	}
+
+	// crashMu serializes the writes to the crash file
+	var crashMu sync.Mutex
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			defer func(ctx context.Context) {
@@ -198,24 +434,60 @@ func New(opts ...Option) func(http.Handler) http.Handler {
 					case error:
 						errMsg = e.Error()
 					default:
-						errMsg = fmt.Sprint(e)
+						if mask != nil {
+							// field names are needed to match "key:value" pairs of structs
+							errMsg = fmt.Sprintf("%+v", e)
+						} else {
+							errMsg = fmt.Sprint(e)
+						}
+					}
+
+					if mask != nil {
+						errMsg = mask.ReplaceAllString(errMsg, "${1}***")
 					}
 
 					var stack []byte
-					if c.IncludeStack {
-						stack = debug.Stack()
+					if c.IncludeStack && c.StackDepth > 0 {
+						stack = captureFrames(c.StackDepth)
+						stack = stack[:min(len(stack), c.StackBufferSize)]
+					} else if c.IncludeStack {
+						buf := stackPool.Get().(*[]byte)
+						defer stackPool.Put(buf)
+						stack = (*buf)[:runtime.Stack(*buf, false)]
 					}
 
 					attrs := []slog.Attr{slog.String("error", errMsg)}
 					if c.IncludeStack {
 						attrs = append(attrs, slog.String("stack", string(stack)))
 					}
+					attrs = append(attrs, buildRequestAttrs(c.RequestFields, r)...)
 
 					c.Logger.LogAttrs(ctx, c.Level, c.Message, attrs...)
 
-					if c.Callback != nil {
-						c.Callback(w, r, rec, stack)
+					if c.Metrics != nil {
+						c.Metrics(r.Pattern)
 					}
+
+					if c.CrashFile != "" {
+						fullStack := stack
+						if !c.IncludeStack || c.StackDepth > 0 {
+							buf := stackPool.Get().(*[]byte)
+							defer stackPool.Put(buf)
+							fullStack = (*buf)[:runtime.Stack(*buf, false)]
+						}
+
+						crashMu.Lock()
+						err := appendCrashEntry(c.CrashFile, r, errMsg, fullStack)
+						crashMu.Unlock()
+						if err != nil {
+							c.Logger.LogAttrs(ctx, c.Level,
+								"failed to write crash file",
+								slog.String("error", err.Error()))
+						}
+					}
+
+					// the stack may be backed by a pooled buffer, while callbacks may retain it
+					runCallbacks(c, w, r, rec, bytes.Clone(stack))
 				}
 			}(r.Context())
 
@@ -223,3 +495,180 @@ func New(opts ...Option) func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// recoverNewPrefix is the function name prefix shared by the closures created by New.
+const recoverNewPrefix = "github.com/paccolamano/golazy/handlers/recover.New."
+
+// captureFrames formats at most depth frames of the current goroutine stack.
+// It must be called from the deferred recovery function: frames up to and
+// including runtime.gopanic, the runtime helpers raising runtime errors (e.g.
+// runtime.sigpanic or runtime.goPanicIndex) and the closures created by New
+// are skipped, so the first frame is the function that panicked.
+func captureFrames(depth int) []byte {
+	pcs := make([]uintptr, depth+32)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var (
+		buf      bytes.Buffer
+		count    int
+		panicked bool
+	)
+	for count < depth {
+		frame, more := frames.Next()
+
+		switch {
+		case !panicked:
+			panicked = frame.Function == "runtime.gopanic"
+		case count == 0 && strings.HasPrefix(frame.Function, "runtime."):
+		case !strings.HasPrefix(frame.Function, recoverNewPrefix):
+			fmt.Fprintf(&buf, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+			count++
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	return buf.Bytes()
+}
+
+// appendCrashEntry appends an entry describing a recovered panic to the file
+// at path, creating it if needed. The entry is written with a single call, so
+// that it is not interleaved with entries written by other processes.
+func appendCrashEntry(path string, r *http.Request, errMsg string, stack []byte) error {
+	var entry bytes.Buffer
+	fmt.Fprintf(&entry, "=== %s %s %s\npanic: %s\n\n", time.Now().UTC().Format(time.RFC3339Nano), r.Method, r.URL.Path, errMsg)
+	entry.Write(stack)
+	entry.WriteString("\n\n")
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(entry.Bytes()); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+func buildRequestAttrs(fields []RequestField, r *http.Request) []slog.Attr {
+	attrs := make([]slog.Attr, 0, len(fields))
+
+	for _, f := range fields {
+		switch f {
+		case RequestFieldMethod:
+			attrs = append(attrs, slog.String("method", r.Method))
+		case RequestFieldPath:
+			attrs = append(attrs, slog.String("path", r.URL.Path))
+		case RequestFieldQuery:
+			attrs = append(attrs, slog.String("query", r.URL.RawQuery))
+		case RequestFieldTraceID:
+			if id := tracer.GetTraceID(r); id != nil {
+				attrs = append(attrs, slog.String("traceID", id.String()))
+			}
+		}
+	}
+
+	return attrs
+}
+
+// runCallbacks invokes ErrorHandler, or Callback, followed by the appended Callbacks in
+// registration order. When more than one callback is configured, each one
+// receives a guarded writer so that only the first callback writing a
+// response is authoritative; writes from the others are discarded.
+func runCallbacks(c *config, w http.ResponseWriter, r *http.Request, rec any, stack []byte) {
+	callbacks := make([]func(http.ResponseWriter, *http.Request, any, []byte), 0, len(c.Callbacks)+1)
+	switch {
+	case c.ErrorHandler != nil:
+		callbacks = append(callbacks, func(w http.ResponseWriter, r *http.Request, rec any, stack []byte) {
+			c.ErrorHandler(w, r, &PanicError{Value: rec, Stack: stack})
+		})
+	case c.Callback != nil:
+		callbacks = append(callbacks, c.Callback)
+	}
+	callbacks = append(callbacks, c.Callbacks...)
+
+	if len(callbacks) == 1 {
+		callbacks[0](w, r, rec, stack)
+		return
+	}
+
+	state := &responseOwner{}
+	for i, cb := range callbacks {
+		cb(&guardedWriter{ResponseWriter: w, state: state, id: i}, r, rec, stack)
+	}
+}
+
+// responseOwner records which callback, if any, wrote the response first.
+type responseOwner struct {
+	owned bool
+	id    int
+}
+
+// guardedWriter is a http.ResponseWriter handed to a single callback. The
+// first guardedWriter that writes becomes the owner of the response and any
+// later write coming from a different callback is silently discarded.
+type guardedWriter struct {
+	http.ResponseWriter
+	state  *responseOwner
+	id     int
+	header http.Header
+}
+
+func (gw *guardedWriter) Header() http.Header {
+	if gw.state.owned && gw.state.id != gw.id {
+		if gw.header == nil {
+			gw.header = make(http.Header)
+		}
+		return gw.header
+	}
+
+	return gw.ResponseWriter.Header()
+}
+
+func (gw *guardedWriter) WriteHeader(code int) {
+	if gw.claim() {
+		gw.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (gw *guardedWriter) Write(b []byte) (int, error) {
+	if gw.claim() {
+		return gw.ResponseWriter.Write(b)
+	}
+
+	return len(b), nil
+}
+
+// claim makes gw the response owner if nobody wrote yet and reports
+// whether gw is allowed to write.
+func (gw *guardedWriter) claim() bool {
+	if !gw.state.owned {
+		gw.state.owned = true
+		gw.state.id = gw.id
+	}
+
+	return gw.state.id == gw.id
+}
+
+// compileFieldMask builds a single case-insensitive regular expression that
+// matches any of the given keys followed by a "=" or ":" separator and a value.
+// The key and separator are captured in the first group so they can be kept
+// while the value is replaced. It returns nil when no keys are given.
+func compileFieldMask(keys []string) *regexp.Regexp {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	quoted := make([]string, 0, len(keys))
+	for _, k := range keys {
+		quoted = append(quoted, regexp.QuoteMeta(k))
+	}
+
+	return regexp.MustCompile(`(?i)((?:` + strings.Join(quoted, "|") + `)\s*[=:]\s*)(?:"[^"]*"|[^\s,;&}\]]+)`)
+}
@@ -4,12 +4,17 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 
+	"github.com/paccolamano/golazy/handlers/tracer"
 	"gotest.tools/v3/assert"
 )
 
@@ -132,6 +137,27 @@ func TestRecoveryWithCustomStatusCode(t *testing.T) {
 	assert.Equal(t, "Bad Gateway", body["error"])
 }
 
+func TestRecoveryWithResponseMarshaler(t *testing.T) {
+	logger := &mockLogger{}
+	h := New(
+		WithLogger(logger),
+		WithStatusCode(http.StatusServiceUnavailable),
+		WithResponseMarshaler(func(status int, recovered any) ([]byte, string) {
+			return []byte(fmt.Sprintf("<error status=%q>%v</error>", fmt.Sprint(status), recovered)), "application/xml"
+		}),
+	)(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		panic("boom")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	assert.Equal(t, rr.Code, http.StatusServiceUnavailable)
+	assert.Equal(t, rr.Header().Get("Content-Type"), "application/xml")
+	assert.Equal(t, rr.Body.String(), `<error status="503">boom</error>`)
+}
+
 func TestRecoveryWithCustomMessageAndLevel(t *testing.T) {
 	logger := &mockLogger{}
 	h := New(
@@ -185,3 +211,454 @@ func TestDefaultCallbackJSONEncodingError(t *testing.T) {
 	assert.Assert(t, len(logger.entries) > 0)
 	assert.Assert(t, strings.Contains(logger.entries[len(logger.entries)-1], "failed to send recovery response"))
 }
+
+func TestRecoveryWithFieldMask(t *testing.T) {
+	logger := &mockLogger{}
+	h := New(
+		WithLogger(logger),
+		WithFieldMask("password", "token"),
+	)(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		panic("login failed: user=alice password=secret Token: \"abc def\"")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Assert(t, strings.Contains(logger.entries[0], "user=alice password=*** Token: ***"))
+	assert.Assert(t, !strings.Contains(logger.entries[0], "secret"))
+	assert.Assert(t, !strings.Contains(logger.entries[0], "abc def"))
+}
+
+func TestRecoveryWithFieldMaskOnStruct(t *testing.T) {
+	type credentials struct {
+		User     string
+		Password string
+	}
+
+	logger := &mockLogger{}
+	h := New(
+		WithLogger(logger),
+		WithFieldMask("password"),
+	)(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		panic(credentials{User: "alice", Password: "secret"})
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	assert.Assert(t, strings.Contains(logger.entries[0], "{User:alice Password:***}"))
+	assert.Assert(t, !strings.Contains(logger.entries[0], "secret"))
+}
+
+func TestRecoveryWithAppendCallback(t *testing.T) {
+	logger := &mockLogger{}
+	var order []string
+
+	h := New(
+		WithLogger(logger),
+		WithCallback(func(w http.ResponseWriter, _ *http.Request, _ any, _ []byte) {
+			order = append(order, "response")
+			w.WriteHeader(http.StatusTeapot)
+			_, _ = w.Write([]byte("custom response"))
+		}),
+		WithAppendCallback(func(_ http.ResponseWriter, _ *http.Request, _ any, _ []byte) {
+			order = append(order, "metrics")
+		}),
+		WithAppendCallback(func(w http.ResponseWriter, _ *http.Request, _ any, _ []byte) {
+			order = append(order, "late response")
+			w.Header().Set("X-Late", "true")
+			w.WriteHeader(http.StatusBadGateway)
+			_, _ = w.Write([]byte("ignored"))
+		}),
+	)(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		panic("boom")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	assert.DeepEqual(t, order, []string{"response", "metrics", "late response"})
+	assert.Equal(t, http.StatusTeapot, rr.Code)
+	assert.Equal(t, "custom response", rr.Body.String())
+	assert.Equal(t, "", rr.Header().Get("X-Late"))
+}
+
+func TestRecoveryWithAppendCallbackKeepsDefault(t *testing.T) {
+	logger := &mockLogger{}
+	var called bool
+
+	h := New(
+		WithLogger(logger),
+		WithAppendCallback(func(_ http.ResponseWriter, _ *http.Request, _ any, _ []byte) {
+			called = true
+		}),
+	)(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		panic("boom")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	assert.Assert(t, called)
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+
+	var body map[string]string
+	err := json.NewDecoder(rr.Body).Decode(&body)
+	assert.NilError(t, err)
+	assert.Equal(t, "Internal Server Error", body["error"])
+}
+
+func TestRecoveryWithRequestFields(t *testing.T) {
+	logger := &mockLogger{}
+	h := tracer.New()(New(
+		WithLogger(logger),
+		WithRequestFields(RequestFieldMethod, RequestFieldPath, RequestFieldTraceID),
+	)(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		panic("boom")
+	})))
+
+	req := httptest.NewRequest(http.MethodPost, "/orders?id=1", nil)
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	traceID := rr.Header().Get("X-Trace-ID")
+	assert.Assert(t, traceID != "")
+	assert.Assert(t, strings.Contains(logger.entries[0], "method=POST"))
+	assert.Assert(t, strings.Contains(logger.entries[0], "path=/orders"))
+	assert.Assert(t, strings.Contains(logger.entries[0], "traceID="+traceID))
+	assert.Assert(t, !strings.Contains(logger.entries[0], "query="))
+}
+
+func TestRecoveryWithStackBufferSize(t *testing.T) {
+	logger := &mockLogger{}
+	var captured []byte
+
+	h := New(
+		WithLogger(logger),
+		WithIncludeStack(true),
+		WithStackBufferSize(64),
+		WithCallback(func(w http.ResponseWriter, _ *http.Request, _ any, stack []byte) {
+			captured = append([]byte(nil), stack...)
+			w.WriteHeader(http.StatusInternalServerError)
+		}),
+	)(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		panic("truncated stack")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	assert.Equal(t, len(captured), 64)
+	assert.Assert(t, strings.HasPrefix(string(captured), "goroutine"))
+	assert.Assert(t, strings.Contains(logger.entries[0], "stack=goroutine"))
+}
+
+func TestRecoveryWithNonPositiveStackBufferSize(t *testing.T) {
+	for _, size := range []int{0, -1} {
+		t.Run(strconv.Itoa(size), func(t *testing.T) {
+			c := &config{StackBufferSize: defaultStackBufferSize}
+			WithStackBufferSize(size)(c)
+			assert.Equal(t, c.StackBufferSize, defaultStackBufferSize)
+
+			var captured []byte
+			h := New(
+				WithLogger(Discard),
+				WithIncludeStack(true),
+				WithStackBufferSize(size),
+				WithCallback(func(_ http.ResponseWriter, _ *http.Request, _ any, stack []byte) {
+					captured = stack
+				}),
+			)(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+				panic("boom")
+			}))
+
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+			assert.Assert(t, strings.Contains(string(captured), "TestRecoveryWithNonPositiveStackBufferSize"))
+		})
+	}
+}
+
+func TestRecoveryCallbacksMayRetainStack(t *testing.T) {
+	var retained [][]byte
+	retain := func(_ http.ResponseWriter, _ *http.Request, _ any, stack []byte) {
+		retained = append(retained, stack)
+	}
+
+	h := New(
+		WithLogger(Discard),
+		WithIncludeStack(true),
+		WithCallback(retain),
+		WithAppendCallback(retain),
+	)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		panic("boom " + r.URL.Path)
+	}))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/first", nil))
+	first := string(retained[0])
+
+	// a second panic reuses the pooled buffer the first stack was captured into
+	for range 10 {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/other", nil))
+	}
+
+	assert.Equal(t, string(retained[0]), first)
+	assert.Equal(t, string(retained[1]), first)
+	assert.Assert(t, strings.HasPrefix(first, "goroutine"))
+}
+
+func BenchmarkRecoveryWithStack(b *testing.B) {
+	h := New(
+		WithLogger(slog.New(slog.DiscardHandler)),
+		WithIncludeStack(true),
+		WithCallback(func(_ http.ResponseWriter, _ *http.Request, _ any, _ []byte) {}),
+	)(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		panic("boom")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rr := httptest.NewRecorder()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		h.ServeHTTP(rr, req)
+	}
+}
+
+func TestRecoveryWithStackDepth(t *testing.T) {
+	logger := &mockLogger{}
+	var captured string
+
+	h := New(
+		WithLogger(logger),
+		WithIncludeStack(true),
+		WithStackDepth(2),
+		WithCallback(func(w http.ResponseWriter, _ *http.Request, _ any, stack []byte) {
+			captured = string(stack)
+			w.WriteHeader(http.StatusInternalServerError)
+		}),
+	)(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		panic("shallow stack")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	lines := strings.Split(strings.TrimSpace(captured), "\n")
+	assert.Equal(t, len(lines), 4)
+	assert.Assert(t, strings.Contains(lines[0], "TestRecoveryWithStackDepth"))
+	assert.Assert(t, strings.HasPrefix(lines[1], "\t"))
+	assert.Assert(t, strings.HasPrefix(lines[3], "\t"))
+	assert.Assert(t, !strings.Contains(captured, "runtime.gopanic"))
+	assert.Assert(t, !strings.Contains(captured, recoverNewPrefix))
+	assert.Assert(t, strings.Contains(logger.entries[0], "TestRecoveryWithStackDepth"))
+}
+
+func TestRecoveryWithStackDepthOnRuntimeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{
+			name: "with nil pointer dereference",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				var req *http.Request
+				_, _ = w.Write([]byte(req.Method))
+			},
+		},
+		{
+			name: "with index out of range",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte{[]byte(r.URL.Path)[len(r.URL.Path)+1]})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := &mockLogger{}
+			var captured string
+
+			h := New(
+				WithLogger(logger),
+				WithIncludeStack(true),
+				WithStackDepth(2),
+				WithCallback(func(w http.ResponseWriter, _ *http.Request, _ any, stack []byte) {
+					captured = string(stack)
+					w.WriteHeader(http.StatusInternalServerError)
+				}),
+			)(tt.handler)
+
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+			lines := strings.Split(strings.TrimSpace(captured), "\n")
+			assert.Assert(t, strings.Contains(lines[0], "TestRecoveryWithStackDepthOnRuntimeErrors"), captured)
+			assert.Assert(t, !strings.Contains(captured, "runtime."), captured)
+			assert.Assert(t, strings.Contains(logger.entries[0], "TestRecoveryWithStackDepthOnRuntimeErrors"))
+		})
+	}
+}
+
+func TestRecoveryWithStackDepthAndBufferSize(t *testing.T) {
+	var captured []byte
+
+	h := New(
+		WithLogger(Discard),
+		WithIncludeStack(true),
+		WithStackDepth(10),
+		WithStackBufferSize(32),
+		WithCallback(func(w http.ResponseWriter, _ *http.Request, _ any, stack []byte) {
+			captured = stack
+			w.WriteHeader(http.StatusInternalServerError)
+		}),
+	)(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		panic("bounded stack")
+	}))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, len(captured), 32)
+	assert.Assert(t, strings.HasPrefix(string(captured), "github.com/paccolamano/golazy/"))
+}
+
+func TestDiscard(t *testing.T) {
+	t.Parallel()
+
+	var l Logger = Discard
+	l.LogAttrs(context.Background(), slog.LevelError, "discarded", slog.String("foo", "bar"))
+
+	handler := New(WithLogger(Discard))(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		panic("boom")
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, rec.Code, http.StatusInternalServerError)
+}
+
+func TestRecoveryWithCrashFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "crash.log")
+	logger := &mockLogger{}
+
+	h := New(
+		WithLogger(logger),
+		WithCrashFile(path),
+	)(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		panic("crash file boom")
+	}))
+
+	for range 2 {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/orders", nil))
+	}
+
+	data, err := os.ReadFile(path)
+	assert.NilError(t, err)
+
+	content := string(data)
+	assert.Equal(t, strings.Count(content, "=== "), 2)
+	assert.Assert(t, strings.Contains(content, "POST /orders\npanic: crash file boom\n"))
+	assert.Assert(t, strings.Contains(content, "goroutine "))
+	assert.Assert(t, strings.Contains(content, "TestRecoveryWithCrashFile"))
+
+	// the log entry does not include the stack, as IncludeStack is not set
+	assert.Assert(t, !strings.Contains(logger.entries[0], "TestRecoveryWithCrashFile"))
+}
+
+func TestRecoveryWithUnwritableCrashFile(t *testing.T) {
+	t.Parallel()
+
+	logger := &mockLogger{}
+	h := New(
+		WithLogger(logger),
+		WithCrashFile(filepath.Join(t.TempDir(), "missing", "crash.log")),
+	)(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		panic("boom")
+	}))
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, rr.Code, http.StatusInternalServerError)
+	assert.Equal(t, len(logger.entries), 2)
+	assert.Assert(t, strings.HasPrefix(logger.entries[1], "failed to write crash file"))
+}
+
+func TestRecoveryWithErrorHandler(t *testing.T) {
+	t.Parallel()
+
+	errBoom := errors.New("boom")
+
+	var got error
+	var appended bool
+	h := New(
+		WithLogger(Discard),
+		WithIncludeStack(true),
+		WithCallback(func(_ http.ResponseWriter, _ *http.Request, _ any, _ []byte) {
+			t.Error("callback should be replaced by the error handler")
+		}),
+		WithErrorHandler(func(w http.ResponseWriter, _ *http.Request, err error) {
+			got = err
+			http.Error(w, "handled", http.StatusServiceUnavailable)
+		}),
+		WithAppendCallback(func(_ http.ResponseWriter, _ *http.Request, _ any, _ []byte) {
+			appended = true
+		}),
+	)(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		panic(fmt.Errorf("wrapped: %w", errBoom))
+	}))
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, rr.Code, http.StatusServiceUnavailable)
+	assert.Assert(t, appended)
+
+	var panicErr *PanicError
+	assert.Assert(t, errors.As(got, &panicErr))
+	assert.Equal(t, panicErr.Error(), "panic: wrapped: boom")
+	assert.Assert(t, strings.Contains(string(panicErr.Stack), "TestRecoveryWithErrorHandler"))
+	assert.ErrorIs(t, got, errBoom)
+}
+
+func TestRecoveryWithMetrics(t *testing.T) {
+	t.Parallel()
+
+	var routes []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", func(_ http.ResponseWriter, _ *http.Request) {
+		panic("boom")
+	})
+	mux.HandleFunc("GET /ok", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	h := New(
+		WithLogger(Discard),
+		WithMetrics(func(route string) {
+			routes = append(routes, route)
+		}),
+	)(mux)
+
+	for _, target := range []string{"/users/1", "/ok", "/users/2"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	assert.DeepEqual(t, routes, []string{"GET /users/{id}", "GET /users/{id}"})
+}
+
+func TestPanicError(t *testing.T) {
+	t.Parallel()
+
+	err := &PanicError{Value: "boom"}
+	assert.Equal(t, err.Error(), "panic: boom")
+	assert.Assert(t, err.Unwrap() == nil)
+}
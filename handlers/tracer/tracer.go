@@ -47,6 +47,7 @@ package tracer
 
 import (
 	"context"
+	"math/rand/v2"
 	"net/http"
 
 	"github.com/google/uuid"
@@ -56,12 +57,17 @@ type traceIDKey string
 
 const (
 	defaultTraceIDKey traceIDKey = "traceID"
+
+	// notSampledKey marks in the request context the requests
+	// that were not sampled.
+	notSampledKey traceIDKey = "notSampled"
 )
 
 // config holds configuration options for the Tracer handler.
 type config struct {
 	contextKey any
 	headerKey  string
+	sampleRate float64
 }
 
 // Option represents a functional option for configuring Tracer handler.
@@ -81,6 +87,16 @@ func WithHeaderKey(key string) Option {
 	}
 }
 
+// WithSampleRate sets the fraction of requests, between 0 and 1, for which a
+// trace ID is generated and propagated. Unsampled requests get no header and
+// no trace ID, and are marked as not sampled in the context (see IsSampled).
+// Default is 1, meaning that every request is sampled.
+func WithSampleRate(rate float64) Option {
+	return func(c *config) {
+		c.sampleRate = rate
+	}
+}
+
 // New returns a handler that generates a unique request ID (UUID) for each incoming HTTP request,
 // attaches it to the response header (default as "X-Trace-ID"), and stores it in the request context using the provided context key.
 //
@@ -101,6 +117,7 @@ func New(opts ...Option) func(http.Handler) http.Handler {
 	c := &config{
 		contextKey: defaultTraceIDKey,
 		headerKey:  "X-Trace-ID",
+		sampleRate: 1,
 	}
 
 	for _, opt := range opts {
@@ -109,6 +126,12 @@ func New(opts ...Option) func(http.Handler) http.Handler {
 
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if c.sampleRate < 1 && rand.Float64() >= c.sampleRate {
+				ctx := context.WithValue(r.Context(), notSampledKey, true)
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+
 			uuid := uuid.New().String()
 			w.Header().Set(c.headerKey, uuid)
 			ctx := context.WithValue(r.Context(), c.contextKey, uuid)
@@ -132,6 +155,18 @@ func GetTraceIDWithKey(r *http.Request, key any) *uuid.UUID {
 	return getTraceID(r, key)
 }
 
+// IsSampled reports whether the request was sampled by New, i.e. whether a
+// trace ID was generated for it. Requests that did not go through a tracer
+// configured with WithSampleRate are reported as sampled.
+func IsSampled(r *http.Request) bool {
+	if r == nil {
+		return true
+	}
+
+	notSampled, _ := r.Context().Value(notSampledKey).(bool)
+	return !notSampled
+}
+
 func getTraceID(r *http.Request, key any) *uuid.UUID {
 	if r == nil {
 		return nil
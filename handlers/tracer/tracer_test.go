@@ -152,3 +152,75 @@ func TestGetTraceIDWithKey(t *testing.T) {
 		assert.Equal(t, traceID.String(), id.String())
 	})
 }
+
+func TestWithSampleRate(t *testing.T) {
+	t.Parallel()
+
+	opts := config{}
+	f := WithSampleRate(0.25)
+	f(&opts)
+
+	assert.Equal(t, opts.sampleRate, 0.25)
+}
+
+func TestNewWithSampleRate(t *testing.T) {
+	t.Parallel()
+
+	const requests = 10000
+
+	var sampled, notSampled int
+	var lastSampled bool
+	handler := New(WithSampleRate(0.3))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastSampled = IsSampled(r)
+		if lastSampled {
+			sampled++
+			assert.Assert(t, GetTraceID(r) != nil)
+		} else {
+			notSampled++
+			assert.Assert(t, GetTraceID(r) == nil)
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for range requests {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tracer", nil))
+
+		hasHeader := w.Result().Header.Get("X-Trace-ID") != ""
+		assert.Equal(t, hasHeader, lastSampled)
+	}
+
+	assert.Equal(t, sampled+notSampled, requests)
+
+	// with 10000 requests the standard deviation is ~0.0046, so
+	// a tolerance of 0.03 makes the test practically deterministic
+	rate := float64(sampled) / requests
+	assert.Assert(t, rate > 0.27 && rate < 0.33, "unexpected sample rate %f", rate)
+}
+
+func TestNewWithZeroSampleRate(t *testing.T) {
+	t.Parallel()
+
+	handler := New(WithSampleRate(0))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Assert(t, !IsSampled(r))
+		assert.Assert(t, GetTraceID(r) == nil)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tracer", nil))
+
+	assert.Equal(t, w.Result().Header.Get("X-Trace-ID"), "")
+}
+
+func TestIsSampled(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	assert.Assert(t, IsSampled(req))
+	assert.Assert(t, IsSampled(nil))
+
+	ctx := context.WithValue(req.Context(), notSampledKey, true)
+	assert.Assert(t, !IsSampled(req.WithContext(ctx)))
+}
@@ -0,0 +1,159 @@
+// Package batch provides a Batcher that accumulates items and hands them over
+// in batches, e.g. for bulk database inserts or bulk API calls.
+//
+// Example usage:
+//
+//	b := batch.New(func(events []Event) {
+//		if err := store.InsertMany(ctx, events); err != nil {
+//			slog.Error("failed to store events", "err", err)
+//		}
+//	}, batch.WithMaxSize(500), batch.WithMaxAge(2*time.Second))
+//	defer b.Close()
+//
+//	b.Add(Event{Name: "signup"})
+package batch
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	defaultMaxSize = 100
+	defaultMaxAge  = time.Second
+)
+
+// config holds configuration options for Batcher.
+type config struct {
+	maxSize int
+	maxAge  time.Duration
+}
+
+// Option defines a functional option used to configure a Batcher.
+type Option func(*config)
+
+// WithMaxSize sets the number of items that triggers a flush. Default is 100.
+func WithMaxSize(n int) Option {
+	return func(c *config) {
+		c.maxSize = n
+	}
+}
+
+// WithMaxAge sets the maximum time an item waits before being flushed,
+// measured from the first item of the batch. Default is 1s. Zero or negative
+// values disable time-based flushes.
+func WithMaxAge(d time.Duration) Option {
+	return func(c *config) {
+		c.maxAge = d
+	}
+}
+
+// Batcher accumulates items and passes them to a flush function when the
+// batch reaches the max size or the max age, whichever comes first.
+// It is safe for concurrent use.
+//
+// The flush function is called with the Batcher locked, so batches are
+// flushed one at a time and in order, and Add blocks while a flush is in
+// progress. The flush function must therefore not call the Batcher methods.
+type Batcher[T any] struct {
+	flush   func([]T)
+	maxSize int
+	maxAge  time.Duration
+
+	mu     sync.Mutex
+	items  []T
+	timer  *time.Timer
+	gen    uint64
+	closed bool
+}
+
+// New creates a new Batcher calling flush with each batch, with optional
+// configuration via functional options. The slice passed to flush is owned
+// by the callee.
+func New[T any](flush func([]T), opts ...Option) *Batcher[T] {
+	c := &config{
+		maxSize: defaultMaxSize,
+		maxAge:  defaultMaxAge,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return &Batcher[T]{
+		flush:   flush,
+		maxSize: max(c.maxSize, 1),
+		maxAge:  c.maxAge,
+	}
+}
+
+// Add appends v to the current batch, flushing it if it reaches the max size.
+// It panics if the Batcher is closed.
+func (b *Batcher[T]) Add(v T) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		panic("batch: Add called after Close")
+	}
+
+	b.items = append(b.items, v)
+
+	if len(b.items) >= b.maxSize {
+		b.flushLocked()
+		return
+	}
+
+	if len(b.items) == 1 && b.maxAge > 0 {
+		gen := b.gen
+		b.timer = time.AfterFunc(b.maxAge, func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+
+			// the batch this timer was started for may already be flushed
+			if b.gen == gen {
+				b.flushLocked()
+			}
+		})
+	}
+}
+
+// Flush immediately flushes the current batch, if not empty.
+func (b *Batcher[T]) Flush() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.flushLocked()
+}
+
+// Close flushes the current batch, if not empty, and releases the resources
+// of the Batcher. Calling Close more than once is a no-op.
+func (b *Batcher[T]) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	b.flushLocked()
+	b.closed = true
+}
+
+// flushLocked hands the current batch over to the flush function and starts
+// a new one. b.mu must be held.
+func (b *Batcher[T]) flushLocked() {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	b.gen++
+
+	if len(b.items) == 0 {
+		return
+	}
+
+	items := b.items
+	b.items = nil
+	b.flush(items)
+}
@@ -0,0 +1,130 @@
+package batch
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
+)
+
+type recorder struct {
+	mu      sync.Mutex
+	batches [][]int
+}
+
+func (r *recorder) flush(items []int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches = append(r.batches, items)
+}
+
+func (r *recorder) get() [][]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([][]int(nil), r.batches...)
+}
+
+func TestBatcher(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should flush when the max size is reached", func(t *testing.T) {
+		t.Parallel()
+
+		r := &recorder{}
+		b := New(r.flush, WithMaxSize(3), WithMaxAge(time.Hour))
+		defer b.Close()
+
+		for i := range 7 {
+			b.Add(i)
+		}
+
+		assert.DeepEqual(t, r.get(), [][]int{{0, 1, 2}, {3, 4, 5}})
+	})
+
+	t.Run("should flush when the max age is reached", func(t *testing.T) {
+		t.Parallel()
+
+		r := &recorder{}
+		b := New(r.flush, WithMaxSize(100), WithMaxAge(20*time.Millisecond))
+		defer b.Close()
+
+		b.Add(1)
+		b.Add(2)
+		assert.Equal(t, len(r.get()), 0)
+
+		assert.Assert(t, waitFor(func() bool { return len(r.get()) == 1 }))
+		assert.DeepEqual(t, r.get(), [][]int{{1, 2}})
+
+		b.Add(3)
+		assert.Assert(t, waitFor(func() bool { return len(r.get()) == 2 }))
+		assert.DeepEqual(t, r.get(), [][]int{{1, 2}, {3}})
+	})
+
+	t.Run("should flush remaining items on close", func(t *testing.T) {
+		t.Parallel()
+
+		r := &recorder{}
+		b := New(r.flush, WithMaxSize(10), WithMaxAge(time.Hour))
+
+		b.Add(1)
+		b.Add(2)
+		b.Close()
+		b.Close()
+
+		assert.DeepEqual(t, r.get(), [][]int{{1, 2}})
+		assert.Assert(t, cmp.Panics(func() { b.Add(3) }))
+	})
+
+	t.Run("should flush on demand", func(t *testing.T) {
+		t.Parallel()
+
+		r := &recorder{}
+		b := New(r.flush, WithMaxAge(0))
+		defer b.Close()
+
+		b.Flush()
+		b.Add(1)
+		b.Flush()
+
+		assert.DeepEqual(t, r.get(), [][]int{{1}})
+	})
+
+	t.Run("should be safe for concurrent use", func(t *testing.T) {
+		t.Parallel()
+
+		r := &recorder{}
+		b := New(r.flush, WithMaxSize(7), WithMaxAge(time.Millisecond))
+
+		var wg sync.WaitGroup
+		for g := range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range 100 {
+					b.Add(g*100 + i)
+				}
+			}()
+		}
+		wg.Wait()
+		b.Close()
+
+		total := 0
+		for _, batch := range r.get() {
+			assert.Assert(t, len(batch) <= 7)
+			total += len(batch)
+		}
+		assert.Equal(t, total, 1000)
+	})
+}
+
+func waitFor(cond func() bool) bool {
+	for range 100 {
+		if cond() {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
@@ -0,0 +1,207 @@
+// Package cache provides a generic in-memory key-value cache with per-entry
+// expiration and optional least-recently-used eviction.
+//
+// Example usage:
+//
+//	users := cache.NewLRU[string, *User](10_000)
+//
+//	users.Set(id, user, 5*time.Minute)
+//
+//	if u, ok := users.Get(id); ok {
+//		// ...
+//	}
+package cache
+
+import (
+	"container/list"
+	"errors"
+	"sync"
+	"time"
+)
+
+// errLoadPanicked is returned to the callers waiting on a GetOrLoad call whose
+// load function panicked.
+var errLoadPanicked = errors.New("cache: load function panicked")
+
+// Cache is a generic in-memory cache. Each entry has its own time-to-live and
+// expired entries are removed lazily, when they are accessed or evicted.
+// When created with NewLRU the number of entries is bounded, and the least
+// recently used ones are evicted first.
+// It is safe for concurrent use.
+type Cache[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[K]*list.Element
+	// order holds the entries from the most to the least recently used.
+	order *list.List
+	// calls holds the loads in progress started by GetOrLoad.
+	calls map[K]*call[V]
+	now   func() time.Time
+}
+
+// entry is a value stored in the cache.
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+// expired reports whether e is expired at the given time.
+func (e *entry[K, V]) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// New creates a new unbounded Cache.
+func New[K comparable, V any]() *Cache[K, V] {
+	return NewLRU[K, V](0)
+}
+
+// NewLRU creates a new Cache holding at most capacity entries: when a new
+// entry exceeds it, the least recently used entry is evicted. Both Get and Set
+// count as a use. A capacity lower than or equal to zero means unbounded.
+func NewLRU[K comparable, V any](capacity int) *Cache[K, V] {
+	return &Cache[K, V]{
+		capacity: max(capacity, 0),
+		entries:  make(map[K]*list.Element),
+		order:    list.New(),
+		calls:    make(map[K]*call[V]),
+		now:      time.Now,
+	}
+}
+
+// Get returns the value stored for key and whether it was found and not expired.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.getLocked(key)
+}
+
+// Set stores value for key, replacing any existing entry. The entry expires
+// after ttl; a ttl lower than or equal to zero means it never expires.
+func (c *Cache[K, V]) Set(key K, value V, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = c.now().Add(ttl)
+	}
+
+	if el, ok := c.entries[key]; ok {
+		e := el.Value.(*entry[K, V])
+		e.value = value
+		e.expiresAt = expiresAt
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&entry[K, V]{key: key, value: value, expiresAt: expiresAt})
+
+	if c.capacity > 0 && c.order.Len() > c.capacity {
+		c.removeElement(c.order.Back())
+	}
+}
+
+// call is a load in progress started by GetOrLoad.
+type call[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// GetOrLoad returns the value stored for key if found and not expired;
+// otherwise it calls load and stores its result with the given ttl.
+// Concurrent callers missing the same key share a single call to load and
+// all receive its result, which protects the underlying source from cache
+// stampedes. Errors returned by load are not cached. If load panics, the
+// panic propagates to the caller that invoked it, while the waiting callers
+// receive an error.
+func (c *Cache[K, V]) GetOrLoad(key K, ttl time.Duration, load func() (V, error)) (V, error) {
+	c.mu.Lock()
+	if v, ok := c.getLocked(key); ok {
+		c.mu.Unlock()
+		return v, nil
+	}
+
+	if cl, ok := c.calls[key]; ok {
+		c.mu.Unlock()
+		<-cl.done
+		return cl.value, cl.err
+	}
+
+	cl := &call[V]{done: make(chan struct{})}
+	c.calls[key] = cl
+	c.mu.Unlock()
+
+	// the deferred cleanup releases the waiting callers even if load panics
+	defer func() {
+		c.mu.Lock()
+		delete(c.calls, key)
+		c.mu.Unlock()
+		close(cl.done)
+	}()
+
+	cl.err = errLoadPanicked
+	cl.value, cl.err = load()
+	if cl.err == nil {
+		c.Set(key, cl.value, ttl)
+	}
+
+	return cl.value, cl.err
+}
+
+// Delete removes the entry stored for key, if any.
+func (c *Cache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		c.removeElement(el)
+	}
+}
+
+// Len returns the number of entries in the cache. It may include expired
+// entries that have not been removed yet.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// Purge removes all the entries from the cache.
+func (c *Cache[K, V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clear(c.entries)
+	c.order.Init()
+}
+
+// getLocked returns the value stored for key, removing it if expired.
+// c.mu must be held.
+func (c *Cache[K, V]) getLocked(key K) (V, bool) {
+	el, ok := c.entries[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+
+	e := el.Value.(*entry[K, V])
+	if e.expired(c.now()) {
+		c.removeElement(el)
+		var zero V
+		return zero, false
+	}
+
+	c.order.MoveToFront(el)
+
+	return e.value, true
+}
+
+// removeElement removes el from the cache. c.mu must be held.
+func (c *Cache[K, V]) removeElement(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.entries, el.Value.(*entry[K, V]).key)
+}
@@ -0,0 +1,243 @@
+package cache
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should store and delete values", func(t *testing.T) {
+		t.Parallel()
+
+		c := New[string, int]()
+		c.Set("a", 1, 0)
+		c.Set("b", 2, 0)
+		c.Set("a", 3, 0)
+
+		v, ok := c.Get("a")
+		assert.Assert(t, ok)
+		assert.Equal(t, v, 3)
+		assert.Equal(t, c.Len(), 2)
+
+		c.Delete("a")
+		_, ok = c.Get("a")
+		assert.Assert(t, !ok)
+		assert.Equal(t, c.Len(), 1)
+	})
+
+	t.Run("should expire values after their ttl", func(t *testing.T) {
+		t.Parallel()
+
+		now := time.Now()
+		c := New[string, int]()
+		c.now = func() time.Time { return now }
+
+		c.Set("short", 1, time.Second)
+		c.Set("forever", 2, 0)
+
+		_, ok := c.Get("short")
+		assert.Assert(t, ok)
+
+		now = now.Add(time.Second)
+
+		_, ok = c.Get("short")
+		assert.Assert(t, !ok)
+		_, ok = c.Get("forever")
+		assert.Assert(t, ok)
+		assert.Equal(t, c.Len(), 1)
+	})
+
+	t.Run("should purge all values", func(t *testing.T) {
+		t.Parallel()
+
+		c := New[string, int]()
+		c.Set("a", 1, 0)
+		c.Set("b", 2, 0)
+		c.Purge()
+
+		assert.Equal(t, c.Len(), 0)
+		_, ok := c.Get("a")
+		assert.Assert(t, !ok)
+	})
+}
+
+func TestNewLRU(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should evict the least recently used entry", func(t *testing.T) {
+		t.Parallel()
+
+		c := NewLRU[string, int](3)
+		c.Set("a", 1, 0)
+		c.Set("b", 2, 0)
+		c.Set("c", 3, 0)
+
+		// "a" becomes the most recently used, so "b" is evicted first
+		_, ok := c.Get("a")
+		assert.Assert(t, ok)
+
+		c.Set("d", 4, 0)
+		_, ok = c.Get("b")
+		assert.Assert(t, !ok)
+
+		c.Set("e", 5, 0)
+		_, ok = c.Get("c")
+		assert.Assert(t, !ok)
+
+		for _, k := range []string{"a", "d", "e"} {
+			_, ok := c.Get(k)
+			assert.Assert(t, ok, k)
+		}
+		assert.Equal(t, c.Len(), 3)
+	})
+
+	t.Run("should combine eviction with expiration", func(t *testing.T) {
+		t.Parallel()
+
+		now := time.Now()
+		c := NewLRU[string, int](2)
+		c.now = func() time.Time { return now }
+
+		c.Set("a", 1, time.Minute)
+		c.Set("b", 2, 0)
+		now = now.Add(time.Minute)
+
+		_, ok := c.Get("a")
+		assert.Assert(t, !ok)
+
+		c.Set("c", 3, 0)
+		assert.Equal(t, c.Len(), 2)
+		_, ok = c.Get("b")
+		assert.Assert(t, ok)
+	})
+
+	t.Run("should respect capacity under concurrent inserts", func(t *testing.T) {
+		t.Parallel()
+
+		const capacity = 50
+
+		c := NewLRU[string, int](capacity)
+
+		var wg sync.WaitGroup
+		for g := range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range 1000 {
+					c.Set(strconv.Itoa(g*1000+i), i, 0)
+					assert.Assert(t, c.Len() <= capacity)
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, c.Len(), capacity)
+	})
+}
+
+func TestGetOrLoad(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should load once under concurrent misses", func(t *testing.T) {
+		t.Parallel()
+
+		c := New[string, int]()
+
+		var calls atomic.Int32
+		release := make(chan struct{})
+		load := func() (int, error) {
+			calls.Add(1)
+			<-release
+			return 42, nil
+		}
+
+		const callers = 20
+
+		var wg sync.WaitGroup
+		results := make([]int, callers)
+		for i := range callers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				v, err := c.GetOrLoad("answer", time.Minute, load)
+				assert.NilError(t, err)
+				results[i] = v
+			}()
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, calls.Load(), int32(1))
+		for _, v := range results {
+			assert.Equal(t, v, 42)
+		}
+
+		v, ok := c.Get("answer")
+		assert.Assert(t, ok)
+		assert.Equal(t, v, 42)
+
+		v, err := c.GetOrLoad("answer", time.Minute, func() (int, error) {
+			t.Fatal("load should not be called for a cached value")
+			return 0, nil
+		})
+		assert.NilError(t, err)
+		assert.Equal(t, v, 42)
+	})
+
+	t.Run("should not cache errors", func(t *testing.T) {
+		t.Parallel()
+
+		c := New[string, int]()
+		errBoom := errors.New("boom")
+
+		_, err := c.GetOrLoad("k", 0, func() (int, error) { return 0, errBoom })
+		assert.ErrorIs(t, err, errBoom)
+		assert.Equal(t, c.Len(), 0)
+
+		v, err := c.GetOrLoad("k", 0, func() (int, error) { return 1, nil })
+		assert.NilError(t, err)
+		assert.Equal(t, v, 1)
+	})
+
+	t.Run("should release waiting callers when load panics", func(t *testing.T) {
+		t.Parallel()
+
+		c := New[string, int]()
+		started := make(chan struct{})
+		release := make(chan struct{})
+
+		go func() {
+			defer func() { _ = recover() }()
+			_, _ = c.GetOrLoad("k", 0, func() (int, error) {
+				close(started)
+				<-release
+				panic("boom")
+			})
+		}()
+
+		<-started
+		errCh := make(chan error)
+		go func() {
+			_, err := c.GetOrLoad("k", 0, func() (int, error) { return 1, nil })
+			errCh <- err
+		}()
+
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+
+		err := <-errCh
+		// the second caller either waited on the panicking load or, if it
+		// arrived after the cleanup, loaded the value itself
+		assert.Assert(t, err == nil || errors.Is(err, errLoadPanicked))
+	})
+}
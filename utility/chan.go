@@ -0,0 +1,81 @@
+package utility
+
+import "context"
+
+// MapChan returns a channel receiving f applied to every value received from in,
+// in order. The returned channel is closed when in is closed or ctx is done,
+// whichever comes first. Values pending when ctx is done are dropped.
+//
+// Example:
+//
+//	lengths := utility.MapChan(ctx, words, func(w string) int { return len(w) })
+//	for n := range lengths {
+//		fmt.Println(n)
+//	}
+func MapChan[A any, B any](ctx context.Context, in <-chan A, f func(A) B) <-chan B {
+	out := make(chan B)
+
+	go func() {
+		defer close(out)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+
+				select {
+				case out <- f(v):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// ToChan returns a channel receiving the elements of s, in order, which is
+// closed once they are all received. The channel is buffered with all the
+// elements, so no goroutine is involved and nothing leaks if it is not drained.
+//
+// Example:
+//
+//	for w := range utility.ToChan(words) {
+//		fmt.Println(w)
+//	}
+func ToChan[T any](s []T) <-chan T {
+	ch := make(chan T, len(s))
+	for _, v := range s {
+		ch <- v
+	}
+	close(ch)
+
+	return ch
+}
+
+// FromChan collects the values received from ch into a slice, until ch is
+// closed or ctx is done, whichever comes first. The values received before
+// ctx is done are returned.
+//
+// Example:
+//
+//	results := utility.FromChan(ctx, utility.MapChan(ctx, jobs, process))
+func FromChan[T any](ctx context.Context, ch <-chan T) []T {
+	var res []T
+	for {
+		select {
+		case <-ctx.Done():
+			return res
+		case v, ok := <-ch:
+			if !ok {
+				return res
+			}
+			res = append(res, v)
+		}
+	}
+}
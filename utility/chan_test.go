@@ -0,0 +1,123 @@
+package utility
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestMapChan(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should transform values and close when input closes", func(t *testing.T) {
+		t.Parallel()
+
+		in := make(chan int)
+		go func() {
+			defer close(in)
+			for i := range 5 {
+				in <- i
+			}
+		}()
+
+		var res []string
+		for v := range MapChan(context.Background(), in, strconv.Itoa) {
+			res = append(res, v)
+		}
+
+		assert.DeepEqual(t, res, []string{"0", "1", "2", "3", "4"})
+	})
+
+	t.Run("should close when context is cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		in := make(chan int)
+		out := MapChan(ctx, in, func(i int) int { return i * 2 })
+
+		in <- 21
+		assert.Equal(t, <-out, 42)
+
+		cancel()
+
+		select {
+		case _, ok := <-out:
+			assert.Assert(t, !ok, "output channel should be closed")
+		case <-time.After(time.Second):
+			t.Fatal("output channel was not closed")
+		}
+	})
+
+	t.Run("should stop when context is cancelled while sending", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		in := make(chan int, 1)
+		in <- 1
+		out := MapChan(ctx, in, func(i int) int { return i })
+
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+
+		deadline := time.After(time.Second)
+		for {
+			select {
+			case _, ok := <-out:
+				if !ok {
+					return
+				}
+			case <-deadline:
+				t.Fatal("output channel was not closed")
+			}
+		}
+	})
+}
+
+func TestToChanFromChan(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should round-trip a slice", func(t *testing.T) {
+		t.Parallel()
+
+		in := []int{1, 2, 3}
+		assert.DeepEqual(t, FromChan(context.Background(), ToChan(in)), in)
+
+		assert.Equal(t, len(FromChan(context.Background(), ToChan([]int(nil)))), 0)
+	})
+
+	t.Run("should compose with MapChan", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		res := FromChan(ctx, MapChan(ctx, ToChan([]int{1, 2, 3}), strconv.Itoa))
+		assert.DeepEqual(t, res, []string{"1", "2", "3"})
+	})
+
+	t.Run("should stop collecting when context is cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		ch := make(chan int)
+
+		go func() {
+			ch <- 1
+			ch <- 2
+			cancel()
+		}()
+
+		done := make(chan []int)
+		go func() {
+			done <- FromChan(ctx, ch)
+		}()
+
+		select {
+		case res := <-done:
+			assert.DeepEqual(t, res, []int{1, 2})
+		case <-time.After(time.Second):
+			t.Fatal("FromChan did not return after cancellation")
+		}
+	})
+}
@@ -0,0 +1,47 @@
+// Package ctxval provides type-safe context values.
+//
+// Each Key is identified by its own address, so two keys never collide even
+// when they share the same name and type, and values are retrieved with their
+// static type without assertions at the call site.
+//
+// Example usage:
+//
+//	var userIDKey = ctxval.NewKey[int64]("userID")
+//
+//	ctx = userIDKey.WithValue(ctx, 42)
+//
+//	if id, ok := userIDKey.Value(ctx); ok {
+//		slog.Info("user found", "id", id)
+//	}
+package ctxval
+
+import "context"
+
+// Key is a typed context key for values of type T. Keys must be created with
+// NewKey and are usually declared as package-level variables.
+type Key[T any] struct {
+	name string
+}
+
+// NewKey creates a new Key. The name is only used for debugging purposes and
+// does not need to be unique.
+func NewKey[T any](name string) *Key[T] {
+	return &Key[T]{name: name}
+}
+
+// String returns the name of the key.
+func (k *Key[T]) String() string {
+	return k.name
+}
+
+// WithValue returns a copy of ctx in which the key is associated with v.
+func (k *Key[T]) WithValue(ctx context.Context, v T) context.Context {
+	return context.WithValue(ctx, k, v)
+}
+
+// Value returns the value associated with the key in ctx and whether it was
+// found. The zero value of T is returned when the key is missing.
+func (k *Key[T]) Value(ctx context.Context) (T, bool) {
+	v, ok := ctx.Value(k).(T)
+	return v, ok
+}
@@ -0,0 +1,59 @@
+package ctxval
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestKey(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should store and retrieve typed values", func(t *testing.T) {
+		key := NewKey[int]("count")
+		ctx := key.WithValue(context.Background(), 42)
+
+		v, ok := key.Value(ctx)
+		assert.Assert(t, ok)
+		assert.Equal(t, v, 42)
+		assert.Equal(t, key.String(), "count")
+	})
+
+	t.Run("should report missing values", func(t *testing.T) {
+		key := NewKey[string]("missing")
+
+		v, ok := key.Value(context.Background())
+		assert.Assert(t, !ok)
+		assert.Equal(t, v, "")
+	})
+
+	t.Run("should not collide between distinct keys", func(t *testing.T) {
+		first := NewKey[string]("name")
+		second := NewKey[string]("name")
+
+		ctx := first.WithValue(context.Background(), "first")
+		ctx = second.WithValue(ctx, "second")
+
+		v, ok := first.Value(ctx)
+		assert.Assert(t, ok)
+		assert.Equal(t, v, "first")
+
+		v, ok = second.Value(ctx)
+		assert.Assert(t, ok)
+		assert.Equal(t, v, "second")
+
+		_, ok = NewKey[string]("name").Value(ctx)
+		assert.Assert(t, !ok)
+	})
+
+	t.Run("should not collide with plain string keys", func(t *testing.T) {
+		type stringKey string
+		key := NewKey[string]("name")
+
+		ctx := context.WithValue(context.Background(), stringKey("name"), "plain")
+
+		_, ok := key.Value(ctx)
+		assert.Assert(t, !ok)
+	})
+}
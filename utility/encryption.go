@@ -1,11 +1,18 @@
 package utility
 
 import (
+	"bytes"
+	"compress/gzip"
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 )
 
 // EncryptionAlgorithm represents the type of encryption algorithm to use.
@@ -14,12 +21,25 @@ type EncryptionAlgorithm string
 const (
 	// EncryptionAlgorithmAESGCM represents AES encryption in GCM mode.
 	EncryptionAlgorithmAESGCM EncryptionAlgorithm = "aes-gcm"
+
+	// EncryptionAlgorithmAESCBCHMAC represents AES-256 encryption in CBC mode
+	// authenticated with HMAC-SHA256 (encrypt-then-MAC), for interoperability
+	// with systems that require CBC. Prefer EncryptionAlgorithmAESGCM otherwise.
+	//
+	// The key is either 64 bytes, the AES key followed by the HMAC key, or a
+	// single 32 bytes key from which both keys are derived with HKDF-SHA256.
+	// The ciphertext is laid out as:
+	//
+	//	IV (16 bytes) || AES-256-CBC(PKCS#7 padded plaintext) || HMAC-SHA256(IV || ciphertext) (32 bytes)
+	//
+	// Decryption verifies the MAC in constant time before decrypting anything.
+	EncryptionAlgorithmAESCBCHMAC EncryptionAlgorithm = "aes-cbc-hmac"
 )
 
 // Encrypt encrypts the given plaintext using the specified encryption algorithm and key.
 //
 // Parameters:
-//   - alg: the encryption algorithm to use (currently only "aes-gcm").
+//   - alg: the encryption algorithm to use ("aes-gcm" or "aes-cbc-hmac").
 //   - key: the encryption key in hexadecimal string format.
 //   - plaintext: the data to encrypt.
 //
@@ -34,6 +54,8 @@ func Encrypt(alg EncryptionAlgorithm, key string, plaintext []byte) ([]byte, err
 	switch alg {
 	case EncryptionAlgorithmAESGCM:
 		return aesGcmEncrypt(key, plaintext)
+	case EncryptionAlgorithmAESCBCHMAC:
+		return aesCbcHmacEncrypt(key, plaintext)
 	default:
 		return nil, errors.New("unknown encryption algorithm")
 	}
@@ -42,7 +64,7 @@ func Encrypt(alg EncryptionAlgorithm, key string, plaintext []byte) ([]byte, err
 // Decrypt decrypts the given ciphertext using the specified encryption algorithm and key.
 //
 // Parameters:
-//   - alg: the encryption algorithm to use (currently only "aes-gcm").
+//   - alg: the encryption algorithm to use ("aes-gcm" or "aes-cbc-hmac").
 //   - key: the encryption key in hexadecimal string format.
 //   - ciphertext: the data to decrypt.
 //
@@ -57,11 +79,152 @@ func Decrypt(alg EncryptionAlgorithm, key string, ciphertext []byte) ([]byte, er
 	switch alg {
 	case EncryptionAlgorithmAESGCM:
 		return aesGcmDecrypt(key, ciphertext)
+	case EncryptionAlgorithmAESCBCHMAC:
+		return aesCbcHmacDecrypt(key, ciphertext)
 	default:
 		return nil, errors.New("unknown encryption algorithm")
 	}
 }
 
+const (
+	// flagUncompressed marks a plaintext stored as is by EncryptCompressed.
+	flagUncompressed byte = 0
+	// flagGzip marks a plaintext compressed with gzip by EncryptCompressed.
+	flagGzip byte = 1
+)
+
+// EncryptCompressed compresses plaintext with gzip and encrypts it like
+// Encrypt, which reduces the ciphertext size of large, compressible data.
+// A flag byte stating whether the data is compressed is prepended to it before
+// encryption, so it is authenticated along with the data: when compression
+// does not shrink the plaintext, it is stored uncompressed. The output must be
+// decrypted with DecryptCompressed.
+//
+// Security note: compression leaks information about the plaintext through the
+// ciphertext length (as exploited by the CRIME and BREACH attacks). It is fine
+// for data at rest, but must not be used when the plaintext mixes secrets with
+// content controlled by an attacker who can observe the ciphertext length.
+//
+// Example usage:
+//
+//	ciphertext, err := EncryptCompressed(EncryptionAlgorithmAESGCM, hexKey, document)
+func EncryptCompressed(alg EncryptionAlgorithm, key string, plaintext []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte(flagGzip)
+
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(plaintext); err != nil {
+		return nil, fmt.Errorf("failed to compress plaintext: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress plaintext: %v", err)
+	}
+
+	payload := buf.Bytes()
+	if len(payload) >= 1+len(plaintext) {
+		payload = append([]byte{flagUncompressed}, plaintext...)
+	}
+
+	return Encrypt(alg, key, payload)
+}
+
+// DecryptCompressed decrypts a ciphertext produced by EncryptCompressed like
+// Decrypt, and decompresses the result if needed.
+//
+// Example usage:
+//
+//	document, err := DecryptCompressed(EncryptionAlgorithmAESGCM, hexKey, ciphertext)
+func DecryptCompressed(alg EncryptionAlgorithm, key string, ciphertext []byte) ([]byte, error) {
+	payload, err := Decrypt(alg, key, ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(payload) == 0 {
+		return nil, errors.New("missing compression flag")
+	}
+
+	switch payload[0] {
+	case flagUncompressed:
+		return payload[1:], nil
+	case flagGzip:
+		zr, err := gzip.NewReader(bytes.NewReader(payload[1:]))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress plaintext: %v", err)
+		}
+
+		plaintext, err := io.ReadAll(zr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress plaintext: %v", err)
+		}
+
+		return plaintext, nil
+	default:
+		return nil, fmt.Errorf("unknown compression flag %d", payload[0])
+	}
+}
+
+// maxKeyIDLength is the maximum length of a key identifier, whose length is
+// encoded on a single byte.
+const maxKeyIDLength = 255
+
+// EncryptWithKeyID encrypts plaintext like Encrypt and prepends keyID to the
+// ciphertext, so that DecryptMultiKey can select the key to decrypt it with.
+// This allows rotating keys: new data is encrypted with the newest key, while
+// data encrypted with older keys can still be decrypted.
+//
+// The output is laid out as:
+//
+//	len(keyID) (1 byte) || keyID || ciphertext
+//
+// The key identifier is stored in clear and is not authenticated, so it must
+// not be secret; tampering with it only selects a key that fails to decrypt.
+// It must be between 1 and 255 bytes long.
+//
+// Example usage:
+//
+//	ciphertext, err := EncryptWithKeyID(EncryptionAlgorithmAESGCM, "2025-06", hexKey, []byte("my secret"))
+func EncryptWithKeyID(alg EncryptionAlgorithm, keyID, key string, plaintext []byte) ([]byte, error) {
+	if keyID == "" || len(keyID) > maxKeyIDLength {
+		return nil, fmt.Errorf("invalid key id length %d: must be between 1 and %d bytes", len(keyID), maxKeyIDLength)
+	}
+
+	ciphertext, err := Encrypt(alg, key, plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, 1+len(keyID)+len(ciphertext))
+	out = append(out, byte(len(keyID)))
+	out = append(out, keyID...)
+	return append(out, ciphertext...), nil
+}
+
+// DecryptMultiKey decrypts a ciphertext produced by EncryptWithKeyID, using
+// the key of keys whose identifier is stored in the ciphertext. keys maps
+// key identifiers to keys in hexadecimal string format. It returns an error if
+// the key identifier is missing from keys.
+//
+// Example usage:
+//
+//	plaintext, err := DecryptMultiKey(EncryptionAlgorithmAESGCM, map[string]string{
+//		"2025-01": oldHexKey,
+//		"2025-06": newHexKey,
+//	}, ciphertext)
+func DecryptMultiKey(alg EncryptionAlgorithm, keys map[string]string, ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) == 0 || len(ciphertext) < 1+int(ciphertext[0]) {
+		return nil, errors.New("ciphertext too short: missing key id")
+	}
+
+	keyID := string(ciphertext[1 : 1+int(ciphertext[0])])
+	key, ok := keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", keyID)
+	}
+
+	return Decrypt(alg, key, ciphertext[1+len(keyID):])
+}
+
 func aesGcmEncrypt(key string, plaintext []byte) ([]byte, error) {
 	bytesKey, err := hex.DecodeString(key)
 	if err != nil {
@@ -104,3 +267,96 @@ func aesGcmDecrypt(key string, ciphertext []byte) ([]byte, error) {
 
 	return decrypted, nil
 }
+
+// cbcHmacKeyInfo is the HKDF info used to derive the AES-CBC and HMAC keys
+// from a single key.
+const cbcHmacKeyInfo = "golazy aes-256-cbc hmac-sha256"
+
+// aesCbcHmacKeys returns the encryption and MAC keys encoded in key.
+func aesCbcHmacKeys(key string) ([]byte, []byte, error) {
+	bytesKey, err := hex.DecodeString(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode key: %v", err)
+	}
+
+	switch len(bytesKey) {
+	case 64:
+	case 32:
+		bytesKey, err = hkdf.Key(sha256.New, bytesKey, nil, cbcHmacKeyInfo, 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to derive keys: %v", err)
+		}
+	default:
+		return nil, nil, fmt.Errorf("invalid key size %d: must be 32 or 64 bytes", len(bytesKey))
+	}
+
+	return bytesKey[:32], bytesKey[32:], nil
+}
+
+func aesCbcHmacEncrypt(key string, plaintext []byte) ([]byte, error) {
+	encKey, macKey, err := aesCbcHmacKeys(key)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create block cipher: %v", err)
+	}
+
+	padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+	padded := append(bytes.Clone(plaintext), bytes.Repeat([]byte{byte(padding)}, padding)...)
+
+	out := make([]byte, aes.BlockSize+len(padded), aes.BlockSize+len(padded)+sha256.Size)
+	iv := out[:aes.BlockSize]
+	if _, err := rand.Read(iv); err != nil {
+		return nil, fmt.Errorf("failed to generate iv: %v", err)
+	}
+
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(out[aes.BlockSize:], padded)
+
+	mac := hmac.New(sha256.New, macKey)
+	mac.Write(out)
+
+	return mac.Sum(out), nil
+}
+
+func aesCbcHmacDecrypt(key string, ciphertext []byte) ([]byte, error) {
+	encKey, macKey, err := aesCbcHmacKeys(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < aes.BlockSize+aes.BlockSize+sha256.Size {
+		return nil, errors.New("ciphertext too short")
+	}
+
+	payload, tag := ciphertext[:len(ciphertext)-sha256.Size], ciphertext[len(ciphertext)-sha256.Size:]
+
+	mac := hmac.New(sha256.New, macKey)
+	mac.Write(payload)
+	if !hmac.Equal(mac.Sum(nil), tag) {
+		return nil, errors.New("failed to authenticate ciphertext: invalid mac")
+	}
+
+	iv, body := payload[:aes.BlockSize], payload[aes.BlockSize:]
+	if len(body)%aes.BlockSize != 0 {
+		return nil, errors.New("ciphertext is not a multiple of the block size")
+	}
+
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create block cipher: %v", err)
+	}
+
+	decrypted := make([]byte, len(body))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(decrypted, body)
+
+	padding := int(decrypted[len(decrypted)-1])
+	if padding == 0 || padding > aes.BlockSize ||
+		!bytes.Equal(decrypted[len(decrypted)-padding:], bytes.Repeat([]byte{byte(padding)}, padding)) {
+		return nil, errors.New("failed to decrypt ciphertext: invalid padding")
+	}
+
+	return decrypted[:len(decrypted)-padding], nil
+}
@@ -1,6 +1,9 @@
 package utility
 
 import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
 	"encoding/hex"
 	"testing"
 
@@ -53,3 +56,231 @@ func TestDecryptAESGCMWithWrongKey(t *testing.T) {
 	_, err = Decrypt(EncryptionAlgorithmAESGCM, wrongKey, ciphertext)
 	assert.ErrorContains(t, err, "failed to open and decrypt ciphertext")
 }
+
+func TestEncryptDecryptAESCBCHMAC(t *testing.T) {
+	combinedKey := hex.EncodeToString(bytes.Repeat([]byte{0x01}, 32))
+	separateKeys := hex.EncodeToString(append(bytes.Repeat([]byte{0x02}, 32), bytes.Repeat([]byte{0x03}, 32)...))
+
+	tests := []struct {
+		name      string
+		key       string
+		plaintext []byte
+	}{
+		{name: "with a combined key", key: combinedKey, plaintext: []byte("this is a secret message")},
+		{name: "with separate keys", key: separateKeys, plaintext: []byte("this is a secret message")},
+		{name: "with a block sized plaintext", key: combinedKey, plaintext: []byte("0123456789abcdef")},
+		{name: "with an empty plaintext", key: combinedKey, plaintext: []byte{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ciphertext, err := Encrypt(EncryptionAlgorithmAESCBCHMAC, tt.key, tt.plaintext)
+			assert.NilError(t, err)
+
+			// iv, padded ciphertext and mac
+			assert.Equal(t, len(ciphertext), 16+(len(tt.plaintext)/16+1)*16+32)
+
+			decrypted, err := Decrypt(EncryptionAlgorithmAESCBCHMAC, tt.key, ciphertext)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, decrypted, tt.plaintext)
+		})
+	}
+
+	t.Run("should use a random iv", func(t *testing.T) {
+		a, err := Encrypt(EncryptionAlgorithmAESCBCHMAC, combinedKey, []byte("secret"))
+		assert.NilError(t, err)
+		b, err := Encrypt(EncryptionAlgorithmAESCBCHMAC, combinedKey, []byte("secret"))
+		assert.NilError(t, err)
+		assert.Assert(t, !bytes.Equal(a, b))
+	})
+
+	t.Run("should reject an invalid key size", func(t *testing.T) {
+		_, err := Encrypt(EncryptionAlgorithmAESCBCHMAC, hex.EncodeToString(make([]byte, 16)), []byte("secret"))
+		assert.ErrorContains(t, err, "invalid key size 16")
+	})
+}
+
+func TestDecryptAESCBCHMACTampered(t *testing.T) {
+	hexKey := hex.EncodeToString(bytes.Repeat([]byte{0x01}, 64))
+
+	ciphertext, err := Encrypt(EncryptionAlgorithmAESCBCHMAC, hexKey, []byte("this is a secret message"))
+	assert.NilError(t, err)
+
+	tests := []struct {
+		name   string
+		tamper func(c []byte) []byte
+		key    string
+	}{
+		{name: "with a tampered iv", tamper: func(c []byte) []byte { c[0] ^= 1; return c }},
+		{name: "with a tampered ciphertext", tamper: func(c []byte) []byte { c[20] ^= 1; return c }},
+		{name: "with a tampered mac", tamper: func(c []byte) []byte { c[len(c)-1] ^= 1; return c }},
+		{name: "with a truncated ciphertext", tamper: func(c []byte) []byte { return append(c[:16], c[32:]...) }},
+		{
+			name:   "with a wrong key",
+			tamper: func(c []byte) []byte { return c },
+			key:    hex.EncodeToString(bytes.Repeat([]byte{0x02}, 64)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := tt.key
+			if key == "" {
+				key = hexKey
+			}
+
+			_, err := Decrypt(EncryptionAlgorithmAESCBCHMAC, key, tt.tamper(bytes.Clone(ciphertext)))
+			assert.ErrorContains(t, err, "invalid mac")
+		})
+	}
+
+	t.Run("should reject a too short ciphertext", func(t *testing.T) {
+		_, err := Decrypt(EncryptionAlgorithmAESCBCHMAC, hexKey, make([]byte, 63))
+		assert.ErrorContains(t, err, "ciphertext too short")
+	})
+}
+
+func TestDecryptAESCBCHMACVerifiesMACFirst(t *testing.T) {
+	encKey, macKey := bytes.Repeat([]byte{0x01}, 32), bytes.Repeat([]byte{0x02}, 32)
+	hexKey := hex.EncodeToString(append(bytes.Clone(encKey), macKey...))
+
+	// a payload that does not decrypt to a validly padded plaintext
+	payload := make([]byte, 16+32)
+	sign := func(payload []byte) []byte {
+		mac := hmac.New(sha256.New, macKey)
+		mac.Write(payload)
+		return mac.Sum(bytes.Clone(payload))
+	}
+
+	_, err := Decrypt(EncryptionAlgorithmAESCBCHMAC, hexKey, sign(payload))
+	assert.ErrorContains(t, err, "invalid padding")
+
+	// with a bad mac the same payload is rejected before being decrypted
+	forged := sign(payload)
+	forged[len(forged)-1] ^= 1
+	_, err = Decrypt(EncryptionAlgorithmAESCBCHMAC, hexKey, forged)
+	assert.ErrorContains(t, err, "invalid mac")
+}
+
+func TestEncryptWithKeyIDDecryptMultiKey(t *testing.T) {
+	keyA := hex.EncodeToString(bytes.Repeat([]byte{0x0a}, 32))
+	keyB := hex.EncodeToString(bytes.Repeat([]byte{0x0b}, 32))
+
+	for _, alg := range []EncryptionAlgorithm{EncryptionAlgorithmAESGCM, EncryptionAlgorithmAESCBCHMAC} {
+		t.Run(string(alg), func(t *testing.T) {
+			ciphertext, err := EncryptWithKeyID(alg, "a", keyA, []byte("encrypted with a"))
+			assert.NilError(t, err)
+			assert.Equal(t, ciphertext[0], byte(1))
+			assert.Equal(t, string(ciphertext[1:2]), "a")
+
+			// key b is added after the rotation and used for new data
+			keys := map[string]string{"a": keyA, "b": keyB}
+
+			newCiphertext, err := EncryptWithKeyID(alg, "b", keyB, []byte("encrypted with b"))
+			assert.NilError(t, err)
+
+			decrypted, err := DecryptMultiKey(alg, keys, ciphertext)
+			assert.NilError(t, err)
+			assert.Equal(t, string(decrypted), "encrypted with a")
+
+			decrypted, err = DecryptMultiKey(alg, keys, newCiphertext)
+			assert.NilError(t, err)
+			assert.Equal(t, string(decrypted), "encrypted with b")
+		})
+	}
+
+	t.Run("should fail with an unknown key id", func(t *testing.T) {
+		ciphertext, err := EncryptWithKeyID(EncryptionAlgorithmAESGCM, "a", keyA, []byte("secret"))
+		assert.NilError(t, err)
+
+		_, err = DecryptMultiKey(EncryptionAlgorithmAESGCM, map[string]string{"b": keyB}, ciphertext)
+		assert.ErrorContains(t, err, `unknown key id "a"`)
+	})
+
+	t.Run("should fail with a tampered key id", func(t *testing.T) {
+		ciphertext, err := EncryptWithKeyID(EncryptionAlgorithmAESGCM, "a", keyA, []byte("secret"))
+		assert.NilError(t, err)
+		ciphertext[1] = 'b'
+
+		_, err = DecryptMultiKey(EncryptionAlgorithmAESGCM, map[string]string{"a": keyA, "b": keyB}, ciphertext)
+		assert.ErrorContains(t, err, "failed to open and decrypt ciphertext")
+	})
+
+	t.Run("should fail with a truncated ciphertext", func(t *testing.T) {
+		_, err := DecryptMultiKey(EncryptionAlgorithmAESGCM, map[string]string{"a": keyA}, nil)
+		assert.ErrorContains(t, err, "missing key id")
+
+		_, err = DecryptMultiKey(EncryptionAlgorithmAESGCM, map[string]string{"a": keyA}, []byte{5, 'a'})
+		assert.ErrorContains(t, err, "missing key id")
+	})
+
+	t.Run("should reject an invalid key id", func(t *testing.T) {
+		_, err := EncryptWithKeyID(EncryptionAlgorithmAESGCM, "", keyA, []byte("secret"))
+		assert.ErrorContains(t, err, "invalid key id length 0")
+
+		_, err = EncryptWithKeyID(EncryptionAlgorithmAESGCM, string(make([]byte, 256)), keyA, []byte("secret"))
+		assert.ErrorContains(t, err, "invalid key id length 256")
+	})
+}
+
+func TestEncryptDecryptCompressed(t *testing.T) {
+	hexKey := hex.EncodeToString(bytes.Repeat([]byte{0x01}, 32))
+	repetitive := bytes.Repeat([]byte("the quick brown fox jumps over the lazy dog. "), 200)
+
+	tests := []struct {
+		name      string
+		plaintext []byte
+	}{
+		{name: "with a repetitive plaintext", plaintext: repetitive},
+		{name: "with an incompressible plaintext", plaintext: []byte("short")},
+		{name: "with an empty plaintext", plaintext: []byte{}},
+	}
+
+	for _, alg := range []EncryptionAlgorithm{EncryptionAlgorithmAESGCM, EncryptionAlgorithmAESCBCHMAC} {
+		for _, tt := range tests {
+			t.Run(string(alg)+" "+tt.name, func(t *testing.T) {
+				ciphertext, err := EncryptCompressed(alg, hexKey, tt.plaintext)
+				assert.NilError(t, err)
+
+				decrypted, err := DecryptCompressed(alg, hexKey, ciphertext)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, decrypted, tt.plaintext)
+			})
+		}
+	}
+
+	t.Run("should produce a smaller ciphertext for a repetitive plaintext", func(t *testing.T) {
+		compressed, err := EncryptCompressed(EncryptionAlgorithmAESGCM, hexKey, repetitive)
+		assert.NilError(t, err)
+
+		uncompressed, err := Encrypt(EncryptionAlgorithmAESGCM, hexKey, repetitive)
+		assert.NilError(t, err)
+
+		assert.Assert(t, len(compressed) < len(uncompressed)/10, "compressed %d, uncompressed %d", len(compressed), len(uncompressed))
+	})
+
+	t.Run("should store an incompressible plaintext as is", func(t *testing.T) {
+		ciphertext, err := EncryptCompressed(EncryptionAlgorithmAESGCM, hexKey, []byte("short"))
+		assert.NilError(t, err)
+
+		payload, err := Decrypt(EncryptionAlgorithmAESGCM, hexKey, ciphertext)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, payload, []byte("\x00short"))
+	})
+
+	t.Run("should fail with an unknown compression flag", func(t *testing.T) {
+		ciphertext, err := Encrypt(EncryptionAlgorithmAESGCM, hexKey, []byte("\x07data"))
+		assert.NilError(t, err)
+
+		_, err = DecryptCompressed(EncryptionAlgorithmAESGCM, hexKey, ciphertext)
+		assert.ErrorContains(t, err, "unknown compression flag 7")
+	})
+
+	t.Run("should fail with a ciphertext not produced by EncryptCompressed", func(t *testing.T) {
+		ciphertext, err := Encrypt(EncryptionAlgorithmAESGCM, hexKey, nil)
+		assert.NilError(t, err)
+
+		_, err = DecryptCompressed(EncryptionAlgorithmAESGCM, hexKey, ciphertext)
+		assert.ErrorContains(t, err, "missing compression flag")
+	})
+}
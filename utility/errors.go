@@ -0,0 +1,31 @@
+package utility
+
+import "errors"
+
+// CombineErrors aggregates errs into a single error, ignoring nil values.
+// It returns nil when every error is nil, the error itself when only one is
+// non-nil, and an errors.Join of the non-nil errors otherwise.
+//
+// It pairs well with MapCollect:
+//
+//	_, errs := utility.MapCollect(input, validate)
+//	if err := utility.CombineErrors(errs...); err != nil {
+//		return err
+//	}
+func CombineErrors(errs ...error) error {
+	var nonNil []error
+	for _, err := range errs {
+		if err != nil {
+			nonNil = append(nonNil, err)
+		}
+	}
+
+	switch len(nonNil) {
+	case 0:
+		return nil
+	case 1:
+		return nonNil[0]
+	default:
+		return errors.Join(nonNil...)
+	}
+}
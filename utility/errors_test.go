@@ -0,0 +1,31 @@
+package utility
+
+import (
+	"errors"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestCombineErrors(t *testing.T) {
+	t.Parallel()
+
+	errA := errors.New("a")
+	errB := errors.New("b")
+
+	t.Run("should return nil without errors", func(t *testing.T) {
+		assert.NilError(t, CombineErrors())
+		assert.NilError(t, CombineErrors(nil, nil))
+	})
+
+	t.Run("should return the only non nil error", func(t *testing.T) {
+		assert.Equal(t, CombineErrors(nil, errA, nil), errA)
+	})
+
+	t.Run("should join the non nil errors", func(t *testing.T) {
+		err := CombineErrors(errA, nil, errB)
+		assert.Error(t, err, "a\nb")
+		assert.ErrorIs(t, err, errA)
+		assert.ErrorIs(t, err, errB)
+	})
+}
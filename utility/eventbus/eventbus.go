@@ -0,0 +1,142 @@
+// Package eventbus provides a small, typed, in-process publish/subscribe bus.
+//
+// Example usage:
+//
+//	bus := eventbus.New[UserCreated](eventbus.WithBufferSize(16))
+//
+//	events, unsubscribe := bus.Subscribe()
+//	defer unsubscribe()
+//
+//	go func() {
+//		for e := range events {
+//			slog.Info("user created", "id", e.ID)
+//		}
+//	}()
+//
+//	bus.Publish(UserCreated{ID: 42})
+package eventbus
+
+import "sync"
+
+const defaultBufferSize = 64
+
+// Policy defines what Publish does when a subscriber buffer is full.
+type Policy int
+
+const (
+	// PolicyDrop discards the event for subscribers whose buffer is full.
+	PolicyDrop Policy = iota
+	// PolicyBlock waits until every subscriber has room for the event.
+	PolicyBlock
+)
+
+// config holds configuration options for Bus.
+type config struct {
+	bufferSize int
+	policy     Policy
+}
+
+// Option defines a functional option used to configure a Bus.
+type Option func(*config)
+
+// WithBufferSize sets the size of each subscriber channel buffer.
+// By default, it is 64.
+func WithBufferSize(n int) Option {
+	return func(c *config) {
+		c.bufferSize = n
+	}
+}
+
+// WithPolicy sets the behavior of Publish when a subscriber buffer is full.
+// By default, it is PolicyDrop.
+func WithPolicy(p Policy) Option {
+	return func(c *config) {
+		c.policy = p
+	}
+}
+
+// subscriber is a single subscription to a Bus.
+type subscriber[T any] struct {
+	ch   chan T
+	done chan struct{}
+	once sync.Once
+}
+
+// Bus delivers published events of type T to every subscriber. It is safe for
+// concurrent use.
+type Bus[T any] struct {
+	bufferSize int
+	policy     Policy
+
+	mu          sync.RWMutex
+	subscribers map[*subscriber[T]]struct{}
+}
+
+// New creates a new Bus with optional configuration via functional options.
+func New[T any](opts ...Option) *Bus[T] {
+	c := &config{
+		bufferSize: defaultBufferSize,
+		policy:     PolicyDrop,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return &Bus[T]{
+		bufferSize:  max(c.bufferSize, 0),
+		policy:      c.policy,
+		subscribers: make(map[*subscriber[T]]struct{}),
+	}
+}
+
+// Subscribe registers a new subscriber and returns the channel on which events
+// are delivered, along with a function that cancels the subscription. Calling
+// unsubscribe closes the channel; it is safe to call it more than once.
+func (b *Bus[T]) Subscribe() (<-chan T, func()) {
+	s := &subscriber[T]{
+		ch:   make(chan T, b.bufferSize),
+		done: make(chan struct{}),
+	}
+
+	b.mu.Lock()
+	b.subscribers[s] = struct{}{}
+	b.mu.Unlock()
+
+	unsubscribe := func() {
+		s.once.Do(func() {
+			// done is closed first to release a Publish blocked on this subscriber.
+			close(s.done)
+
+			b.mu.Lock()
+			delete(b.subscribers, s)
+			b.mu.Unlock()
+
+			close(s.ch)
+		})
+	}
+
+	return s.ch, unsubscribe
+}
+
+// Publish delivers v to every current subscriber according to the configured
+// Policy.
+func (b *Bus[T]) Publish(v T) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for s := range b.subscribers {
+		if b.policy == PolicyBlock {
+			select {
+			case s.ch <- v:
+			case <-s.done:
+			}
+			continue
+		}
+
+		select {
+		case s.ch <- v:
+		default:
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package eventbus
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestBus(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should deliver events to every subscriber", func(t *testing.T) {
+		t.Parallel()
+
+		bus := New[int]()
+		ch1, unsubscribe1 := bus.Subscribe()
+		defer unsubscribe1()
+		ch2, unsubscribe2 := bus.Subscribe()
+		defer unsubscribe2()
+
+		bus.Publish(1)
+		bus.Publish(2)
+
+		assert.Equal(t, <-ch1, 1)
+		assert.Equal(t, <-ch1, 2)
+		assert.Equal(t, <-ch2, 1)
+		assert.Equal(t, <-ch2, 2)
+	})
+
+	t.Run("should stop delivering after unsubscribe", func(t *testing.T) {
+		t.Parallel()
+
+		bus := New[string]()
+		ch, unsubscribe := bus.Subscribe()
+
+		bus.Publish("before")
+		unsubscribe()
+		unsubscribe()
+		bus.Publish("after")
+
+		var received []string
+		for v := range ch {
+			received = append(received, v)
+		}
+		assert.DeepEqual(t, received, []string{"before"})
+	})
+
+	t.Run("should drop events when the buffer is full", func(t *testing.T) {
+		t.Parallel()
+
+		bus := New[int](WithBufferSize(1))
+		ch, unsubscribe := bus.Subscribe()
+
+		bus.Publish(1)
+		bus.Publish(2)
+		unsubscribe()
+
+		var received []int
+		for v := range ch {
+			received = append(received, v)
+		}
+		assert.DeepEqual(t, received, []int{1})
+	})
+
+	t.Run("should block until the subscriber has room", func(t *testing.T) {
+		t.Parallel()
+
+		bus := New[int](WithBufferSize(0), WithPolicy(PolicyBlock))
+		ch, unsubscribe := bus.Subscribe()
+		defer unsubscribe()
+
+		go func() {
+			for i := range 3 {
+				bus.Publish(i)
+			}
+		}()
+
+		for i := range 3 {
+			assert.Equal(t, <-ch, i)
+		}
+	})
+
+	t.Run("should release a blocked publish on unsubscribe", func(t *testing.T) {
+		t.Parallel()
+
+		bus := New[int](WithBufferSize(0), WithPolicy(PolicyBlock))
+		_, unsubscribe := bus.Subscribe()
+
+		published := make(chan struct{})
+		go func() {
+			bus.Publish(1)
+			close(published)
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+		unsubscribe()
+
+		select {
+		case <-published:
+		case <-time.After(time.Second):
+			t.Fatal("publish is still blocked")
+		}
+	})
+
+	t.Run("should be safe for concurrent use", func(t *testing.T) {
+		t.Parallel()
+
+		bus := New[int]()
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ch, unsubscribe := bus.Subscribe()
+				bus.Publish(1)
+				<-ch
+				unsubscribe()
+			}()
+		}
+		wg.Wait()
+	})
+}
@@ -1,7 +1,12 @@
 package utility
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
 	"errors"
+	"fmt"
+	"unicode"
+	"unicode/utf8"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -30,3 +35,93 @@ func CompareHashAndPlain(hash, plain string) (bool, error) {
 
 	return true, nil
 }
+
+// PasswordPolicy defines the requirements checked by ValidatePassword.
+// Zero values disable the corresponding requirement.
+type PasswordPolicy struct {
+	// MinLength is the minimum number of characters (runes).
+	MinLength int
+
+	// MaxLength is the maximum number of bytes. bcrypt ignores anything
+	// past 72 bytes, so it should not exceed 72 for passwords hashed with Hash.
+	MaxLength int
+
+	// RequireUpper requires at least one uppercase letter.
+	RequireUpper bool
+
+	// RequireLower requires at least one lowercase letter.
+	RequireLower bool
+
+	// RequireDigit requires at least one digit.
+	RequireDigit bool
+
+	// RequireSymbol requires at least one character that is neither
+	// a letter, a digit nor a space (e.g. punctuation).
+	RequireSymbol bool
+}
+
+// ValidatePassword checks password against policy. It returns nil if all the
+// requirements are met, otherwise an error joining one error per unmet requirement.
+//
+// Example:
+//
+//	err := ValidatePassword(pwd, PasswordPolicy{MinLength: 12, MaxLength: 72, RequireDigit: true})
+func ValidatePassword(password string, policy PasswordPolicy) error {
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case !unicode.IsLetter(r) && !unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+
+	var errs []error
+	if policy.MinLength > 0 && utf8.RuneCountInString(password) < policy.MinLength {
+		errs = append(errs, fmt.Errorf("password must be at least %d characters long", policy.MinLength))
+	}
+	if policy.MaxLength > 0 && len(password) > policy.MaxLength {
+		errs = append(errs, fmt.Errorf("password must be at most %d bytes long", policy.MaxLength))
+	}
+	if policy.RequireUpper && !hasUpper {
+		errs = append(errs, errors.New("password must contain at least one uppercase letter"))
+	}
+	if policy.RequireLower && !hasLower {
+		errs = append(errs, errors.New("password must contain at least one lowercase letter"))
+	}
+	if policy.RequireDigit && !hasDigit {
+		errs = append(errs, errors.New("password must contain at least one digit"))
+	}
+	if policy.RequireSymbol && !hasSymbol {
+		errs = append(errs, errors.New("password must contain at least one symbol"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// HashLongSafe generates a bcrypt hash of the given plain-text password after
+// pre-hashing it, so that passwords longer than bcrypt's 72-byte limit are
+// neither rejected nor truncated and every byte of the input is significant.
+//
+// The construction is bcrypt(base64(sha256(password))), using the standard
+// base64 encoding with padding (44 bytes). Hashes produced by HashLongSafe must
+// be verified with CompareHashAndPlainLongSafe, not with CompareHashAndPlain.
+func HashLongSafe(password string) (string, error) {
+	return Hash(preHash(password))
+}
+
+// CompareHashAndPlainLongSafe compares a hash generated by HashLongSafe with
+// a plain-text password. It behaves like CompareHashAndPlain.
+func CompareHashAndPlainLongSafe(hash, plain string) (bool, error) {
+	return CompareHashAndPlain(hash, preHash(plain))
+}
+
+func preHash(password string) string {
+	sum := sha256.Sum256([]byte(password))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
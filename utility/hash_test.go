@@ -1,6 +1,7 @@
 package utility
 
 import (
+	"strings"
 	"testing"
 
 	"gotest.tools/v3/assert"
@@ -36,3 +37,109 @@ func TestCompareHashAndPlain(t *testing.T) {
 	assert.ErrorContains(t, err, "hashedSecret too short")
 	assert.Assert(t, !match)
 }
+
+func TestValidatePassword(t *testing.T) {
+	t.Parallel()
+
+	policy := PasswordPolicy{
+		MinLength:     8,
+		MaxLength:     72,
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+	}
+
+	tests := []struct {
+		name         string
+		password     string
+		expectedErrs []string
+	}{
+		{
+			name:     "valid password",
+			password: "Secur3!pwd",
+		},
+		{
+			name:         "too short",
+			password:     "Ab1!",
+			expectedErrs: []string{"password must be at least 8 characters long"},
+		},
+		{
+			name:         "too long",
+			password:     "Ab1!" + strings.Repeat("a", 69),
+			expectedErrs: []string{"password must be at most 72 bytes long"},
+		},
+		{
+			name:         "missing uppercase",
+			password:     "secur3!pwd",
+			expectedErrs: []string{"password must contain at least one uppercase letter"},
+		},
+		{
+			name:         "missing lowercase",
+			password:     "SECUR3!PWD",
+			expectedErrs: []string{"password must contain at least one lowercase letter"},
+		},
+		{
+			name:         "missing digit",
+			password:     "Secure!pwd",
+			expectedErrs: []string{"password must contain at least one digit"},
+		},
+		{
+			name:         "missing symbol",
+			password:     "Secur3pwd",
+			expectedErrs: []string{"password must contain at least one symbol"},
+		},
+		{
+			name:     "multiple unmet requirements",
+			password: "abc",
+			expectedErrs: []string{
+				"password must be at least 8 characters long",
+				"password must contain at least one uppercase letter",
+				"password must contain at least one digit",
+				"password must contain at least one symbol",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePassword(tt.password, policy)
+			if len(tt.expectedErrs) == 0 {
+				assert.NilError(t, err)
+				return
+			}
+
+			assert.Equal(t, err.Error(), strings.Join(tt.expectedErrs, "\n"))
+		})
+	}
+
+	t.Run("empty policy", func(t *testing.T) {
+		assert.NilError(t, ValidatePassword("", PasswordPolicy{}))
+	})
+}
+
+func TestHashLongSafe(t *testing.T) {
+	t.Parallel()
+
+	prefix := strings.Repeat("a", 72)
+	first, second := prefix+"first", prefix+"second"
+
+	hash, err := HashLongSafe(first)
+	assert.NilError(t, err)
+
+	match, err := CompareHashAndPlainLongSafe(hash, first)
+	assert.NilError(t, err)
+	assert.Assert(t, match)
+
+	match, err = CompareHashAndPlainLongSafe(hash, second)
+	assert.NilError(t, err)
+	assert.Assert(t, !match)
+
+	otherHash, err := HashLongSafe(second)
+	assert.NilError(t, err)
+	assert.Assert(t, hash != otherHash)
+
+	// plain bcrypt cannot deal with the full input
+	_, err = Hash(first)
+	assert.ErrorContains(t, err, "password length exceeds 72 bytes")
+}
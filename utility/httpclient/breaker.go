@@ -0,0 +1,200 @@
+package httpclient
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultFailureThreshold = 5
+	defaultCooldown         = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned by CircuitBreaker when a request is rejected
+// because the circuit is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState represents the state of a CircuitBreaker.
+type CircuitState int
+
+const (
+	// StateClosed lets every request through.
+	StateClosed CircuitState = iota
+	// StateOpen rejects every request with ErrCircuitOpen.
+	StateOpen
+	// StateHalfOpen lets a single probe request through to test the upstream.
+	StateHalfOpen
+)
+
+// String returns the name of the state.
+func (s CircuitState) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// breakerConfig holds configuration options for CircuitBreaker.
+type breakerConfig struct {
+	failureThreshold int
+	cooldown         time.Duration
+}
+
+// BreakerOption defines a functional option used to configure a CircuitBreaker.
+type BreakerOption func(*breakerConfig)
+
+// WithFailureThreshold sets the number of consecutive failures that opens the
+// circuit. By default, it is 5.
+func WithFailureThreshold(n int) BreakerOption {
+	return func(c *breakerConfig) {
+		c.failureThreshold = n
+	}
+}
+
+// WithCooldown sets how long the circuit stays open before letting a probe
+// request through. By default, it is 30s.
+func WithCooldown(d time.Duration) BreakerOption {
+	return func(c *breakerConfig) {
+		c.cooldown = d
+	}
+}
+
+// CircuitBreaker is an http.RoundTripper that stops calling the wrapped
+// transport when it keeps failing. A failure is a network error or a 5xx
+// status code.
+//
+// The circuit starts closed. After the configured number of consecutive
+// failures it opens and requests fail fast with ErrCircuitOpen. Once the
+// cooldown has elapsed it becomes half-open and a single probe request is
+// let through: on success the circuit closes again, on failure it reopens.
+type CircuitBreaker struct {
+	next             http.RoundTripper
+	failureThreshold int
+	cooldown         time.Duration
+	now              func() time.Time
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewCircuitBreaker wraps next, or http.DefaultTransport if nil, in a
+// CircuitBreaker with optional configuration via functional options.
+//
+// Example:
+//
+//	client := &http.Client{
+//		Transport: httpclient.NewCircuitBreaker(
+//			httpclient.NewRetryTransport(),
+//			httpclient.WithFailureThreshold(3),
+//			httpclient.WithCooldown(10*time.Second),
+//		),
+//	}
+func NewCircuitBreaker(next http.RoundTripper, opts ...BreakerOption) *CircuitBreaker {
+	c := &breakerConfig{
+		failureThreshold: defaultFailureThreshold,
+		cooldown:         defaultCooldown,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &CircuitBreaker{
+		next:             next,
+		failureThreshold: max(c.failureThreshold, 1),
+		cooldown:         c.cooldown,
+		now:              time.Now,
+	}
+}
+
+// State returns the current state of the circuit.
+func (cb *CircuitBreaker) State() CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == StateOpen && cb.now().Sub(cb.openedAt) >= cb.cooldown {
+		return StateHalfOpen
+	}
+	return cb.state
+}
+
+// RoundTrip implements http.RoundTripper.
+func (cb *CircuitBreaker) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !cb.allow() {
+		return nil, ErrCircuitOpen
+	}
+
+	resp, err := cb.next.RoundTrip(req)
+	cb.record(err != nil || resp.StatusCode >= http.StatusInternalServerError)
+
+	return resp, err
+}
+
+// allow reports whether a request can go through, moving an open circuit to
+// half-open once the cooldown has elapsed.
+func (cb *CircuitBreaker) allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case StateOpen:
+		if cb.now().Sub(cb.openedAt) < cb.cooldown {
+			return false
+		}
+		cb.state = StateHalfOpen
+		cb.probing = true
+		return true
+	case StateHalfOpen:
+		if cb.probing {
+			return false
+		}
+		cb.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// record updates the circuit with the outcome of a request.
+func (cb *CircuitBreaker) record(failed bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == StateHalfOpen {
+		cb.probing = false
+		if failed {
+			cb.state = StateOpen
+			cb.openedAt = cb.now()
+			return
+		}
+		cb.state = StateClosed
+		cb.failures = 0
+		return
+	}
+
+	if !failed {
+		cb.failures = 0
+		return
+	}
+
+	cb.failures++
+	if cb.state == StateClosed && cb.failures >= cb.failureThreshold {
+		cb.state = StateOpen
+		cb.openedAt = cb.now()
+	}
+}
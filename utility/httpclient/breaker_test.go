@@ -0,0 +1,102 @@
+package httpclient
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	var status int
+	var calls int
+	backend := roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		calls++
+		if status == 0 {
+			return nil, errors.New("connection refused")
+		}
+		return &http.Response{StatusCode: status, Body: http.NoBody}, nil
+	})
+
+	now := time.Now()
+	cb := NewCircuitBreaker(backend, WithFailureThreshold(2), WithCooldown(time.Minute))
+	cb.now = func() time.Time { return now }
+
+	do := func() error {
+		resp, err := cb.RoundTrip(httptest.NewRequest(http.MethodGet, "/", nil))
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+		return err
+	}
+
+	// closed: failures below the threshold are let through
+	status = http.StatusInternalServerError
+	assert.NilError(t, do())
+	assert.Equal(t, cb.State(), StateClosed)
+
+	// closed -> open
+	status = 0
+	assert.ErrorContains(t, do(), "connection refused")
+	assert.Equal(t, cb.State(), StateOpen)
+
+	// open: requests are short-circuited
+	assert.ErrorIs(t, do(), ErrCircuitOpen)
+	assert.Equal(t, calls, 2)
+
+	// open -> half-open -> open on failed probe
+	now = now.Add(time.Minute)
+	assert.Equal(t, cb.State(), StateHalfOpen)
+	assert.ErrorContains(t, do(), "connection refused")
+	assert.Equal(t, cb.State(), StateOpen)
+	assert.ErrorIs(t, do(), ErrCircuitOpen)
+	assert.Equal(t, calls, 3)
+
+	// open -> half-open -> closed on successful probe
+	now = now.Add(time.Minute)
+	status = http.StatusOK
+	assert.NilError(t, do())
+	assert.Equal(t, cb.State(), StateClosed)
+	assert.Equal(t, calls, 4)
+
+	// closed: a success resets the consecutive failures
+	status = http.StatusBadGateway
+	assert.NilError(t, do())
+	status = http.StatusOK
+	assert.NilError(t, do())
+	status = http.StatusBadGateway
+	assert.NilError(t, do())
+	assert.Equal(t, cb.State(), StateClosed)
+}
+
+func TestCircuitBreakerSingleProbe(t *testing.T) {
+	t.Parallel()
+
+	cb := NewCircuitBreaker(roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return nil, errors.New("boom")
+	}), WithFailureThreshold(1), WithCooldown(0))
+
+	_, _ = cb.RoundTrip(httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Assert(t, cb.allow())
+	assert.Assert(t, !cb.allow())
+}
+
+func TestCircuitStateString(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, StateClosed.String(), "closed")
+	assert.Equal(t, StateOpen.String(), "open")
+	assert.Equal(t, StateHalfOpen.String(), "half-open")
+	assert.Equal(t, CircuitState(42).String(), "unknown")
+}
@@ -0,0 +1,12 @@
+// Package httpclient provides http.RoundTripper implementations that make
+// outbound HTTP calls more resilient, such as automatic retries and circuit
+// breaking.
+//
+// Transports are meant to be composed and plugged into an http.Client:
+//
+//	client := &http.Client{
+//		Transport: httpclient.NewRetryTransport(
+//			httpclient.WithMaxRetries(5),
+//		),
+//	}
+package httpclient
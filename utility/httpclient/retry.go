@@ -0,0 +1,179 @@
+package httpclient
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultMaxRetries = 3
+	defaultBaseDelay  = 100 * time.Millisecond
+	defaultMaxDelay   = 5 * time.Second
+)
+
+// retryConfig holds configuration options for RetryTransport.
+type retryConfig struct {
+	base       http.RoundTripper
+	maxRetries int
+	baseDelay  time.Duration
+	maxDelay   time.Duration
+}
+
+// RetryOption defines a functional option used to configure a RetryTransport.
+type RetryOption func(*retryConfig)
+
+// WithBaseTransport sets the http.RoundTripper used to perform the requests.
+// By default, it uses http.DefaultTransport.
+func WithBaseTransport(rt http.RoundTripper) RetryOption {
+	return func(c *retryConfig) {
+		c.base = rt
+	}
+}
+
+// WithMaxRetries sets the maximum number of retries performed after the first
+// attempt. By default, it is 3. Zero disables retries.
+func WithMaxRetries(n int) RetryOption {
+	return func(c *retryConfig) {
+		c.maxRetries = n
+	}
+}
+
+// WithBackoff sets the delay before the first retry and the upper bound for
+// any delay. The delay doubles at every retry. By default, they are 100ms and 5s.
+func WithBackoff(base, maxDelay time.Duration) RetryOption {
+	return func(c *retryConfig) {
+		c.baseDelay = base
+		c.maxDelay = maxDelay
+	}
+}
+
+// RetryTransport is an http.RoundTripper that retries idempotent requests
+// failing with a network error or a 5xx status code, waiting with an
+// exponential backoff between attempts. When the response carries a
+// Retry-After header, its delay is used instead, capped by the max delay.
+//
+// Requests with a body are retried only if their GetBody function is set, as
+// done by http.NewRequest for common body types, so that the body can be
+// rewound before every attempt.
+type RetryTransport struct {
+	base       http.RoundTripper
+	maxRetries int
+	baseDelay  time.Duration
+	maxDelay   time.Duration
+}
+
+// NewRetryTransport creates a new RetryTransport with optional configuration
+// via functional options.
+func NewRetryTransport(opts ...RetryOption) *RetryTransport {
+	c := &retryConfig{
+		base:       http.DefaultTransport,
+		maxRetries: defaultMaxRetries,
+		baseDelay:  defaultBaseDelay,
+		maxDelay:   defaultMaxDelay,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return &RetryTransport{
+		base:       c.base,
+		maxRetries: c.maxRetries,
+		baseDelay:  c.baseDelay,
+		maxDelay:   c.maxDelay,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isRetryable(req) {
+		return t.base.RoundTrip(req)
+	}
+
+	for attempt := 0; ; attempt++ {
+		r := req
+		if attempt > 0 && req.Body != nil && req.Body != http.NoBody {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r = req.Clone(req.Context())
+			r.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(r)
+		if attempt >= t.maxRetries || !shouldRetry(resp, err) {
+			return resp, err
+		}
+
+		delay := t.backoff(attempt, resp)
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the delay to wait before the next attempt.
+func (t *RetryTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return min(d, t.maxDelay)
+		}
+	}
+
+	delay := t.baseDelay << attempt
+	if delay <= 0 || delay > t.maxDelay {
+		return t.maxDelay
+	}
+	return delay
+}
+
+// isRetryable reports whether req is idempotent and can be sent again.
+func isRetryable(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace,
+		http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+
+	hasBody := req.Body != nil && req.Body != http.NoBody
+	return !hasBody || req.GetBody != nil
+}
+
+// shouldRetry reports whether the outcome of an attempt deserves a retry.
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode >= http.StatusInternalServerError
+}
+
+// parseRetryAfter parses a Retry-After header value, expressed either in
+// seconds or as an HTTP date.
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(v); err == nil {
+		return max(time.Until(date), 0), true
+	}
+
+	return 0, false
+}
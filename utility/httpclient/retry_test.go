@@ -0,0 +1,148 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestRetryTransport(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should retry until success", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			if calls.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write([]byte("ok"))
+		}))
+		defer srv.Close()
+
+		client := &http.Client{Transport: NewRetryTransport(WithBackoff(time.Millisecond, 10*time.Millisecond))}
+		resp, err := client.Get(srv.URL)
+		assert.NilError(t, err)
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		assert.NilError(t, err)
+		assert.Equal(t, resp.StatusCode, http.StatusOK)
+		assert.Equal(t, string(body), "ok")
+		assert.Equal(t, calls.Load(), int32(3))
+	})
+
+	t.Run("should give up after max retries", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer srv.Close()
+
+		client := &http.Client{Transport: NewRetryTransport(
+			WithMaxRetries(2),
+			WithBackoff(time.Millisecond, 10*time.Millisecond),
+		)}
+		resp, err := client.Get(srv.URL)
+		assert.NilError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, resp.StatusCode, http.StatusInternalServerError)
+		assert.Equal(t, calls.Load(), int32(3))
+	})
+
+	t.Run("should rewind the body on retry", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		var bodies []string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(b))
+			if calls.Add(1) == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+			}
+		}))
+		defer srv.Close()
+
+		req, err := http.NewRequest(http.MethodPut, srv.URL, strings.NewReader("payload"))
+		assert.NilError(t, err)
+
+		client := &http.Client{Transport: NewRetryTransport(WithBackoff(time.Millisecond, 10*time.Millisecond))}
+		resp, err := client.Do(req)
+		assert.NilError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, resp.StatusCode, http.StatusOK)
+		assert.DeepEqual(t, bodies, []string{"payload", "payload"})
+	})
+
+	t.Run("should not retry non idempotent requests", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer srv.Close()
+
+		client := &http.Client{Transport: NewRetryTransport(WithBackoff(time.Millisecond, 10*time.Millisecond))}
+		resp, err := client.Post(srv.URL, "text/plain", strings.NewReader("payload"))
+		assert.NilError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, resp.StatusCode, http.StatusServiceUnavailable)
+		assert.Equal(t, calls.Load(), int32(1))
+	})
+
+	t.Run("should stop waiting when the context is done", func(t *testing.T) {
+		t.Parallel()
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer srv.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+		assert.NilError(t, err)
+
+		client := &http.Client{Transport: NewRetryTransport(WithBackoff(time.Second, time.Second))}
+		_, err = client.Do(req)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestRetryTransportBackoff(t *testing.T) {
+	t.Parallel()
+
+	rt := NewRetryTransport(WithBackoff(100*time.Millisecond, time.Second))
+
+	assert.Equal(t, rt.backoff(0, nil), 100*time.Millisecond)
+	assert.Equal(t, rt.backoff(2, nil), 400*time.Millisecond)
+	assert.Equal(t, rt.backoff(10, nil), time.Second)
+
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"0"}}}
+	assert.Equal(t, rt.backoff(3, resp), time.Duration(0))
+
+	resp.Header.Set("Retry-After", "120")
+	assert.Equal(t, rt.backoff(0, resp), time.Second)
+
+	resp.Header.Set("Retry-After", "invalid")
+	assert.Equal(t, rt.backoff(1, resp), 200*time.Millisecond)
+}
@@ -0,0 +1,152 @@
+package httputil
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ClientIP returns the IP address of the client that issued r.
+//
+// Without trusted proxies, the X-Real-IP header is returned when present,
+// otherwise the host part of r.RemoteAddr.
+//
+// When trusted proxies are given, forwarding headers are only honored if the
+// direct peer (r.RemoteAddr) belongs to one of them. In that case the
+// X-Forwarded-For chain is walked from right to left, skipping trusted proxies,
+// and the first untrusted address is returned. If the chain only contains
+// trusted proxies, X-Real-IP is used, falling back to r.RemoteAddr.
+//
+// Example:
+//
+//	_, proxies, _ := net.ParseCIDR("10.0.0.0/8")
+//	ip := httputil.ClientIP(r, proxies)
+func ClientIP(r *http.Request, trustedProxies ...*net.IPNet) string {
+	remoteIP := remoteAddrIP(r.RemoteAddr)
+
+	if len(trustedProxies) == 0 {
+		if ip := strings.TrimSpace(r.Header.Get("X-Real-IP")); ip != "" {
+			return ip
+		}
+		return remoteIP
+	}
+
+	if !isTrusted(net.ParseIP(remoteIP), trustedProxies) {
+		return remoteIP
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		ip := net.ParseIP(hop)
+		if ip == nil {
+			continue
+		}
+		if !isTrusted(ip, trustedProxies) {
+			return hop
+		}
+	}
+
+	if ip := strings.TrimSpace(r.Header.Get("X-Real-IP")); ip != "" {
+		return ip
+	}
+
+	return remoteIP
+}
+
+// remoteAddrIP returns the host part of a "host:port" address, or the address
+// itself if it has no port.
+func remoteAddrIP(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
+
+// isTrusted reports whether ip belongs to one of the given networks.
+func isTrusted(ip net.IP, trustedProxies []*net.IPNet) bool {
+	if ip == nil {
+		return false
+	}
+	for _, network := range trustedProxies {
+		if network != nil && network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientKeyConfig holds the configuration of ClientKey.
+type clientKeyConfig struct {
+	trustedProxies []*net.IPNet
+	ipv6PrefixLen  int
+}
+
+// ClientKeyOption is a functional option used to configure ClientKey.
+type ClientKeyOption func(*clientKeyConfig)
+
+// WithKeyTrustedProxies sets the trusted proxies used to resolve the client
+// IP, as in ClientIP.
+func WithKeyTrustedProxies(proxies ...*net.IPNet) ClientKeyOption {
+	return func(c *clientKeyConfig) {
+		c.trustedProxies = append(c.trustedProxies, proxies...)
+	}
+}
+
+// WithIPv6Prefix collapses IPv6 client addresses to their network of the given
+// prefix length, e.g. 64, which is the smallest network usually assigned to a
+// single subscriber. This prevents a client from bypassing a per-client limit
+// by rotating addresses within its own network. Values outside 1-127 disable
+// the normalization, which is the default.
+func WithIPv6Prefix(bits int) ClientKeyOption {
+	return func(c *clientKeyConfig) {
+		c.ipv6PrefixLen = bits
+	}
+}
+
+// ClientKey returns a key identifying the client that issued r, suitable for
+// rate limiting. Since clients must not be able to choose their own key,
+// forwarding headers are ignored unless trusted proxies are set with
+// WithKeyTrustedProxies, in which case the client IP is resolved by ClientIP.
+// A resolved value that is not an IP address (e.g. a malformed X-Real-IP
+// header) falls back to the host part of r.RemoteAddr.
+//
+// The IP is normalized so that IPv4-mapped IPv6 addresses are returned as
+// IPv4 and, with WithIPv6Prefix, IPv6 addresses are collapsed to their network
+// in CIDR notation.
+//
+// Example:
+//
+//	key := httputil.ClientKey(r, httputil.WithIPv6Prefix(64))
+//	// "203.0.113.7" or "2001:db8:1:2::/64"
+func ClientKey(r *http.Request, opts ...ClientKeyOption) string {
+	c := &clientKeyConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	ip := remoteAddrIP(r.RemoteAddr)
+	if len(c.trustedProxies) > 0 {
+		ip = ClientIP(r, c.trustedProxies...)
+	}
+
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		ip = remoteAddrIP(r.RemoteAddr)
+		if addr, err = netip.ParseAddr(ip); err != nil {
+			return ip
+		}
+	}
+	addr = addr.Unmap().WithZone("")
+
+	if addr.Is6() && c.ipv6PrefixLen > 0 && c.ipv6PrefixLen < 128 {
+		prefix, err := addr.Prefix(c.ipv6PrefixLen)
+		if err == nil {
+			return prefix.String()
+		}
+	}
+
+	return addr.String()
+}
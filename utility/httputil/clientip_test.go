@@ -0,0 +1,198 @@
+package httputil
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestClientIP(t *testing.T) {
+	t.Parallel()
+
+	_, proxies, err := net.ParseCIDR("10.0.0.0/8")
+	assert.NilError(t, err)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		trusted    []*net.IPNet
+		expected   string
+	}{
+		{
+			name:       "with remote address only",
+			remoteAddr: "192.0.2.1:1234",
+			expected:   "192.0.2.1",
+		},
+		{
+			name:       "with remote address without port",
+			remoteAddr: "192.0.2.1",
+			expected:   "192.0.2.1",
+		},
+		{
+			name:       "with x-real-ip and no trusted proxies",
+			remoteAddr: "192.0.2.1:1234",
+			headers:    map[string]string{"X-Real-IP": "203.0.113.7"},
+			expected:   "203.0.113.7",
+		},
+		{
+			name:       "with x-forwarded-for and no trusted proxies",
+			remoteAddr: "192.0.2.1:1234",
+			headers:    map[string]string{"X-Forwarded-For": "203.0.113.7"},
+			expected:   "192.0.2.1",
+		},
+		{
+			name:       "with untrusted peer",
+			remoteAddr: "192.0.2.1:1234",
+			headers:    map[string]string{"X-Forwarded-For": "203.0.113.7", "X-Real-IP": "203.0.113.8"},
+			trusted:    []*net.IPNet{proxies},
+			expected:   "192.0.2.1",
+		},
+		{
+			name:       "with trusted peer and forwarded chain",
+			remoteAddr: "10.0.0.1:1234",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1, 203.0.113.7, 10.0.0.2"},
+			trusted:    []*net.IPNet{proxies},
+			expected:   "203.0.113.7",
+		},
+		{
+			name:       "with trusted peer and x-real-ip",
+			remoteAddr: "10.0.0.1:1234",
+			headers:    map[string]string{"X-Real-IP": "203.0.113.8"},
+			trusted:    []*net.IPNet{proxies},
+			expected:   "203.0.113.8",
+		},
+		{
+			name:       "with trusted peer and only trusted hops",
+			remoteAddr: "10.0.0.1:1234",
+			headers:    map[string]string{"X-Forwarded-For": "10.0.0.3, invalid"},
+			trusted:    []*net.IPNet{proxies},
+			expected:   "10.0.0.1",
+		},
+		{
+			name:       "with ipv6 remote address",
+			remoteAddr: "[2001:db8::1]:1234",
+			expected:   "2001:db8::1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			assert.Equal(t, ClientIP(req, tt.trusted...), tt.expected)
+		})
+	}
+}
+
+func TestClientKey(t *testing.T) {
+	t.Parallel()
+
+	_, proxies, err := net.ParseCIDR("10.0.0.0/8")
+	assert.NilError(t, err)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		opts       []ClientKeyOption
+		expected   string
+	}{
+		{
+			name:       "with ipv4 address",
+			remoteAddr: "192.0.2.1:1234",
+			opts:       []ClientKeyOption{WithIPv6Prefix(64)},
+			expected:   "192.0.2.1",
+		},
+		{
+			name:       "with ipv4-mapped ipv6 address",
+			remoteAddr: "[::ffff:192.0.2.1]:1234",
+			opts:       []ClientKeyOption{WithIPv6Prefix(64)},
+			expected:   "192.0.2.1",
+		},
+		{
+			name:       "with ipv6 address without normalization",
+			remoteAddr: "[2001:db8:1:2:aaaa:bbbb:cccc:dddd]:1234",
+			expected:   "2001:db8:1:2:aaaa:bbbb:cccc:dddd",
+		},
+		{
+			name:       "with ipv6 address collapsed to its /64",
+			remoteAddr: "[2001:db8:1:2:aaaa:bbbb:cccc:dddd]:1234",
+			opts:       []ClientKeyOption{WithIPv6Prefix(64)},
+			expected:   "2001:db8:1:2::/64",
+		},
+		{
+			name:       "with ipv6 zone collapsed to its /64",
+			remoteAddr: "[fe80::1%eth0]:1234",
+			opts:       []ClientKeyOption{WithIPv6Prefix(64)},
+			expected:   "fe80::/64",
+		},
+		{
+			name:       "with out of range prefix",
+			remoteAddr: "[2001:db8::1]:1234",
+			opts:       []ClientKeyOption{WithIPv6Prefix(128)},
+			expected:   "2001:db8::1",
+		},
+		{
+			name:       "with forwarded ipv6 address from a trusted proxy",
+			remoteAddr: "10.0.0.1:1234",
+			headers:    map[string]string{"X-Forwarded-For": "2001:db8:1:2::42"},
+			opts:       []ClientKeyOption{WithKeyTrustedProxies(proxies), WithIPv6Prefix(48)},
+			expected:   "2001:db8:1::/48",
+		},
+		{
+			name:       "with forwarding headers and no trusted proxies",
+			remoteAddr: "192.0.2.1:1234",
+			headers:    map[string]string{"X-Real-IP": "198.51.100.7", "X-Forwarded-For": "198.51.100.8"},
+			expected:   "192.0.2.1",
+		},
+		{
+			name:       "with invalid address from a trusted proxy",
+			remoteAddr: "10.0.0.1:1234",
+			headers:    map[string]string{"X-Real-IP": "unknown"},
+			opts:       []ClientKeyOption{WithKeyTrustedProxies(proxies), WithIPv6Prefix(64)},
+			expected:   "10.0.0.1",
+		},
+		{
+			name:       "with invalid remote address",
+			remoteAddr: "pipe",
+			expected:   "pipe",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			assert.Equal(t, ClientKey(req, tt.opts...), tt.expected)
+		})
+	}
+
+	t.Run("should produce the same key for addresses in the same /64", func(t *testing.T) {
+		t.Parallel()
+
+		key := func(remoteAddr string) string {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = remoteAddr
+			return ClientKey(req, WithIPv6Prefix(64))
+		}
+
+		assert.Equal(t, key("[2001:db8:1:2::1]:1234"), key("[2001:db8:1:2:ffff::9]:5678"))
+		assert.Assert(t, key("[2001:db8:1:2::1]:1234") != key("[2001:db8:1:3::1]:1234"))
+	})
+}
@@ -0,0 +1,37 @@
+package httputil
+
+import (
+	"io"
+	"net/http"
+)
+
+// maxDrainBytes is the maximum number of bytes read by DrainAndClose. Bodies
+// larger than that are not worth reading just to reuse the connection.
+const maxDrainBytes = 256 << 10
+
+// DrainAndClose reads the remaining request body, up to 256 KiB, discarding it,
+// and then closes it. Fully consuming the body allows the underlying connection
+// to be reused (HTTP keep-alive). It is a no-op if r or its body is nil.
+//
+// Example:
+//
+//	func handler(w http.ResponseWriter, r *http.Request) {
+//		defer httputil.DrainAndClose(r)
+//		...
+//	}
+func DrainAndClose(r *http.Request) error {
+	if r == nil || r.Body == nil {
+		return nil
+	}
+
+	_, err := io.CopyN(io.Discard, r.Body, maxDrainBytes)
+	if err == io.EOF {
+		err = nil
+	}
+
+	if closeErr := r.Body.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
@@ -0,0 +1,70 @@
+package httputil
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+type trackingBody struct {
+	io.Reader
+	closed  bool
+	readErr error
+}
+
+func (b *trackingBody) Read(p []byte) (int, error) {
+	if b.readErr != nil {
+		return 0, b.readErr
+	}
+	return b.Reader.Read(p)
+}
+
+func (b *trackingBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestDrainAndClose(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should read and close the body", func(t *testing.T) {
+		reader := strings.NewReader(strings.Repeat("a", 1024))
+		body := &trackingBody{Reader: reader}
+		req := httptest.NewRequest(http.MethodPost, "/", body)
+
+		err := DrainAndClose(req)
+		assert.NilError(t, err)
+		assert.Equal(t, reader.Len(), 0)
+		assert.Assert(t, body.closed)
+	})
+
+	t.Run("should read at most the max drain size", func(t *testing.T) {
+		reader := strings.NewReader(strings.Repeat("a", maxDrainBytes+10))
+		body := &trackingBody{Reader: reader}
+		req := httptest.NewRequest(http.MethodPost, "/", body)
+
+		err := DrainAndClose(req)
+		assert.NilError(t, err)
+		assert.Equal(t, reader.Len(), 10)
+		assert.Assert(t, body.closed)
+	})
+
+	t.Run("should close the body on read error", func(t *testing.T) {
+		body := &trackingBody{Reader: strings.NewReader(""), readErr: errors.New("read failed")}
+		req := httptest.NewRequest(http.MethodPost, "/", body)
+
+		err := DrainAndClose(req)
+		assert.ErrorContains(t, err, "read failed")
+		assert.Assert(t, body.closed)
+	})
+
+	t.Run("should ignore nil request and body", func(t *testing.T) {
+		assert.NilError(t, DrainAndClose(nil))
+		assert.NilError(t, DrainAndClose(&http.Request{}))
+	})
+}
@@ -0,0 +1,58 @@
+package httputil
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// HTTPError is an error carrying the HTTP status code and the message to send
+// to the client. It is meant to be returned by application code and written
+// by WriteHTTPError.
+//
+// Example:
+//
+//	return &httputil.HTTPError{Status: http.StatusNotFound, Message: "user not found", Code: "USER_NOT_FOUND"}
+type HTTPError struct {
+	// Status is the HTTP status code of the response.
+	Status int `json:"-"`
+
+	// Message is the human readable error message sent to the client.
+	Message string `json:"error"`
+
+	// Code is an optional machine readable error code.
+	Code string `json:"code,omitempty"`
+}
+
+// Error returns the error message.
+func (e *HTTPError) Error() string {
+	return e.Message
+}
+
+// WriteHTTPError writes err as a JSON response such as
+// {"error":"user not found","code":"USER_NOT_FOUND"}.
+//
+// If err is, or wraps, an *HTTPError, its status, message and code are used.
+// A zero status defaults to 500 and an empty message to the status text.
+// Any other error results in a 500 response with a generic message, so that
+// internal details are not leaked to the client.
+func WriteHTTPError(w http.ResponseWriter, err error) error {
+	body := HTTPError{Status: http.StatusInternalServerError}
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		body = *httpErr
+		if body.Status == 0 {
+			body.Status = http.StatusInternalServerError
+		}
+	}
+
+	if body.Message == "" {
+		body.Message = http.StatusText(body.Status)
+	}
+
+	w.Header().Set("Content-Type", contentTypeJSON+"; charset=utf-8")
+	w.WriteHeader(body.Status)
+
+	return json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,67 @@
+package httputil
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestWriteHTTPError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "with http error",
+			err:            &HTTPError{Status: http.StatusNotFound, Message: "user not found", Code: "USER_NOT_FOUND"},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"user not found","code":"USER_NOT_FOUND"}` + "\n",
+		},
+		{
+			name:           "with wrapped http error",
+			err:            fmt.Errorf("loading user: %w", &HTTPError{Status: http.StatusConflict, Message: "already exists"}),
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"already exists"}` + "\n",
+		},
+		{
+			name:           "with http error without message and status",
+			err:            &HTTPError{},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"error":"Internal Server Error"}` + "\n",
+		},
+		{
+			name:           "with plain error",
+			err:            errors.New("database password is wrong"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"error":"Internal Server Error"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			err := WriteHTTPError(rec, tt.err)
+			assert.NilError(t, err)
+			assert.Equal(t, rec.Code, tt.expectedStatus)
+			assert.Equal(t, rec.Header().Get("Content-Type"), "application/json; charset=utf-8")
+			assert.Equal(t, rec.Body.String(), tt.expectedBody)
+		})
+	}
+}
+
+func TestHTTPErrorError(t *testing.T) {
+	t.Parallel()
+
+	var err error = &HTTPError{Status: http.StatusBadRequest, Message: "invalid input"}
+	assert.Error(t, err, "invalid input")
+}
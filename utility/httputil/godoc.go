@@ -0,0 +1,3 @@
+// Package httputil provides small helpers for HTTP handlers and middlewares
+// built on top of the standard library net/http package.
+package httputil
@@ -0,0 +1,183 @@
+package httputil
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timeType is the reflect.Type of time.Time.
+var timeType = reflect.TypeFor[time.Time]()
+
+// DecodeQuery binds the query parameters of r to a new struct of type T.
+//
+// Only the exported fields tagged with `query:"name"` are bound; the tag may
+// carry a ",required" flag. A `default:"value"` tag provides the value used
+// when the parameter is missing or empty. Supported field types are string,
+// bool, signed and unsigned integers, floats, time.Time (RFC 3339 or a
+// "2006-01-02" date) and []string, which collects repeated parameters
+// (a default value is split on commas).
+//
+// A missing required parameter or a value that cannot be parsed results in an
+// *HTTPError with status 400, ready to be written with WriteHTTPError.
+// Unsupported field types or a T that is not a struct are programming errors
+// and are reported as plain errors.
+//
+// Example:
+//
+//	type listParams struct {
+//		Query string    `query:"q,required"`
+//		Limit int       `query:"limit" default:"20"`
+//		Tags  []string  `query:"tag"`
+//		Since time.Time `query:"since"`
+//	}
+//
+//	params, err := httputil.DecodeQuery[listParams](r)
+//	if err != nil {
+//		httputil.WriteHTTPError(w, err)
+//		return
+//	}
+func DecodeQuery[T any](r *http.Request) (*T, error) {
+	out := new(T)
+
+	v := reflect.ValueOf(out).Elem()
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("httputil: DecodeQuery: %s is not a struct", v.Type())
+	}
+
+	query := r.URL.Query()
+
+	for i := range v.NumField() {
+		field := v.Type().Field(i)
+		tag, ok := field.Tag.Lookup("query")
+		if !ok || tag == "-" || !field.IsExported() {
+			continue
+		}
+
+		name, flags, _ := strings.Cut(tag, ",")
+		required := flags == "required"
+
+		values := nonEmpty(query[name])
+		if len(values) == 0 {
+			if def, ok := field.Tag.Lookup("default"); ok {
+				values = []string{def}
+				if field.Type.Kind() == reflect.Slice {
+					values = strings.Split(def, ",")
+				}
+			}
+		}
+
+		if len(values) == 0 {
+			if required {
+				return nil, &HTTPError{
+					Status:  http.StatusBadRequest,
+					Message: fmt.Sprintf("missing required query parameter %q", name),
+				}
+			}
+			continue
+		}
+
+		if err := setField(v.Field(i), values); err != nil {
+			var httpErr *HTTPError
+			if errors.As(err, &httpErr) {
+				httpErr.Message = fmt.Sprintf("invalid query parameter %q: %s", name, httpErr.Message)
+				return nil, httpErr
+			}
+			return nil, fmt.Errorf("httputil: DecodeQuery: field %s: %w", field.Name, err)
+		}
+	}
+
+	return out, nil
+}
+
+// setField parses values into f. Parsing failures are reported as an
+// *HTTPError, unsupported field types as a plain error.
+func setField(f reflect.Value, values []string) error {
+	if f.Type() == timeType {
+		t, err := parseTime(values[0])
+		if err != nil {
+			return badRequest(err)
+		}
+		f.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	raw := values[0]
+
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return badRequest(err)
+		}
+		f.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, f.Type().Bits())
+		if err != nil {
+			return badRequest(err)
+		}
+		f.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, f.Type().Bits())
+		if err != nil {
+			return badRequest(err)
+		}
+		f.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(raw, f.Type().Bits())
+		if err != nil {
+			return badRequest(err)
+		}
+		f.SetFloat(n)
+	case reflect.Slice:
+		if f.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported type %s", f.Type())
+		}
+		f.Set(reflect.ValueOf(values).Convert(f.Type()))
+	default:
+		return fmt.Errorf("unsupported type %s", f.Type())
+	}
+
+	return nil
+}
+
+// parseTime parses s as an RFC 3339 timestamp or as a date.
+func parseTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected an RFC 3339 timestamp or a %s date", time.DateOnly)
+	}
+
+	return t, nil
+}
+
+// badRequest wraps a parsing error into an *HTTPError with status 400.
+func badRequest(err error) *HTTPError {
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		err = numErr.Err
+	}
+
+	return &HTTPError{Status: http.StatusBadRequest, Message: err.Error()}
+}
+
+// nonEmpty returns values without the empty strings.
+func nonEmpty(values []string) []string {
+	var out []string
+	for _, v := range values {
+		if v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,126 @@
+package httputil
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+type queryParams struct {
+	Query   string    `query:"q,required"`
+	Limit   int       `query:"limit" default:"20"`
+	Page    uint8     `query:"page"`
+	Ratio   float64   `query:"ratio"`
+	Active  bool      `query:"active"`
+	Tags    []string  `query:"tag" default:"a,b"`
+	Since   time.Time `query:"since"`
+	Ignored string
+	Skipped string `query:"-"`
+}
+
+func TestDecodeQuery(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should bind every supported type", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodGet,
+			"/?q=go&limit=5&page=3&ratio=0.5&active=true&tag=x&tag=y&since=2025-01-02T03:04:05Z&Ignored=1&Skipped=1", nil)
+
+		params, err := DecodeQuery[queryParams](req)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, *params, queryParams{
+			Query:  "go",
+			Limit:  5,
+			Page:   3,
+			Ratio:  0.5,
+			Active: true,
+			Tags:   []string{"x", "y"},
+			Since:  time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		})
+	})
+
+	t.Run("should apply defaults for missing or empty parameters", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodGet, "/?q=go&limit=&since=2025-01-02", nil)
+
+		params, err := DecodeQuery[queryParams](req)
+		assert.NilError(t, err)
+		assert.Equal(t, params.Limit, 20)
+		assert.DeepEqual(t, params.Tags, []string{"a", "b"})
+		assert.Equal(t, params.Since, time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC))
+		assert.Equal(t, params.Active, false)
+	})
+
+	tests := []struct {
+		name            string
+		url             string
+		expectedMessage string
+	}{
+		{
+			name:            "with missing required parameter",
+			url:             "/?limit=5",
+			expectedMessage: `missing required query parameter "q"`,
+		},
+		{
+			name:            "with invalid int",
+			url:             "/?q=go&limit=ten",
+			expectedMessage: `invalid query parameter "limit": invalid syntax`,
+		},
+		{
+			name:            "with out of range int",
+			url:             "/?q=go&page=300",
+			expectedMessage: `invalid query parameter "page": value out of range`,
+		},
+		{
+			name:            "with invalid bool",
+			url:             "/?q=go&active=maybe",
+			expectedMessage: `invalid query parameter "active": invalid syntax`,
+		},
+		{
+			name:            "with invalid time",
+			url:             "/?q=go&since=yesterday",
+			expectedMessage: `invalid query parameter "since": expected an RFC 3339 timestamp or a 2006-01-02 date`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+
+			params, err := DecodeQuery[queryParams](req)
+			assert.Assert(t, params == nil)
+
+			var httpErr *HTTPError
+			assert.Assert(t, errors.As(err, &httpErr))
+			assert.Equal(t, httpErr.Status, http.StatusBadRequest)
+			assert.Equal(t, httpErr.Message, tt.expectedMessage)
+		})
+	}
+
+	t.Run("should reject unsupported types", func(t *testing.T) {
+		t.Parallel()
+
+		type unsupported struct {
+			IDs []int `query:"id"`
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/?id=1", nil)
+
+		_, err := DecodeQuery[unsupported](req)
+		assert.ErrorContains(t, err, "unsupported type []int")
+
+		var httpErr *HTTPError
+		assert.Assert(t, !errors.As(err, &httpErr))
+
+		_, err = DecodeQuery[string](req)
+		assert.ErrorContains(t, err, "is not a struct")
+	})
+}
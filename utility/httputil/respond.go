@@ -0,0 +1,104 @@
+package httputil
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	contentTypeJSON = "application/json"
+	contentTypeXML  = "application/xml"
+)
+
+// Respond writes v to w with the given status code, encoding it as JSON or XML
+// depending on the request Accept header, honoring quality values. JSON is
+// used when the header is missing, prefers a wildcard or does not mention an
+// acceptable supported media type.
+// The Content-Type header is set accordingly.
+//
+// The value is encoded before anything is written, so on encoding errors the
+// response is left untouched and the error is returned to the caller.
+//
+// Example:
+//
+//	func handler(w http.ResponseWriter, r *http.Request) {
+//		if err := httputil.Respond(w, r, http.StatusOK, user); err != nil {
+//			http.Error(w, "internal server error", http.StatusInternalServerError)
+//		}
+//	}
+func Respond(w http.ResponseWriter, r *http.Request, status int, v any) error {
+	contentType := negotiateContentType(r)
+
+	var buf bytes.Buffer
+	var err error
+	switch contentType {
+	case contentTypeXML:
+		err = xml.NewEncoder(&buf).Encode(v)
+	default:
+		err = json.NewEncoder(&buf).Encode(v)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot encode response: %w", err)
+	}
+
+	w.Header().Set("Content-Type", contentType+"; charset=utf-8")
+	w.WriteHeader(status)
+	_, err = w.Write(buf.Bytes())
+
+	return err
+}
+
+// negotiateContentType returns the supported media type with the highest
+// quality value ("q" parameter, 1 by default) in the request Accept header,
+// preferring the first listed on ties. Media types with q=0 or an invalid
+// quality value are not acceptable. It falls back to JSON.
+func negotiateContentType(r *http.Request) string {
+	if r == nil {
+		return contentTypeJSON
+	}
+
+	best, bestQ := contentTypeJSON, 0.0
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, _ := strings.Cut(part, ";")
+
+		var contentType string
+		switch strings.ToLower(strings.TrimSpace(mediaType)) {
+		case contentTypeJSON, "*/*", "application/*":
+			contentType = contentTypeJSON
+		case contentTypeXML, "text/xml":
+			contentType = contentTypeXML
+		default:
+			continue
+		}
+
+		if q := acceptQuality(params); q > bestQ {
+			best, bestQ = contentType, q
+		}
+	}
+
+	return best
+}
+
+// acceptQuality returns the quality value found in the parameters of an
+// Accept header element, 1 if missing, or 0 if invalid.
+func acceptQuality(params string) float64 {
+	for _, param := range strings.Split(params, ";") {
+		name, value, _ := strings.Cut(param, "=")
+		if !strings.EqualFold(strings.TrimSpace(name), "q") {
+			continue
+		}
+
+		q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || q < 0 || q > 1 {
+			return 0
+		}
+		return q
+	}
+
+	return 1
+}
@@ -0,0 +1,109 @@
+package httputil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+type respondPayload struct {
+	Name string `json:"name" xml:"name"`
+}
+
+func TestRespond(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name                string
+		accept              string
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:                "with json accept header",
+			accept:              "application/json",
+			expectedContentType: "application/json; charset=utf-8",
+			expectedBody:        "{\"name\":\"golazy\"}\n",
+		},
+		{
+			name:                "with xml accept header",
+			accept:              "application/xml",
+			expectedContentType: "application/xml; charset=utf-8",
+			expectedBody:        "<respondPayload><name>golazy</name></respondPayload>",
+		},
+		{
+			name:                "with text xml and quality values",
+			accept:              "text/html;q=0.9, text/xml;q=0.8",
+			expectedContentType: "application/xml; charset=utf-8",
+			expectedBody:        "<respondPayload><name>golazy</name></respondPayload>",
+		},
+		{
+			name:                "with json not acceptable",
+			accept:              "application/json;q=0, application/xml",
+			expectedContentType: "application/xml; charset=utf-8",
+			expectedBody:        "<respondPayload><name>golazy</name></respondPayload>",
+		},
+		{
+			name:                "with low quality wildcard",
+			accept:              "*/*;q=0.1, application/xml",
+			expectedContentType: "application/xml; charset=utf-8",
+			expectedBody:        "<respondPayload><name>golazy</name></respondPayload>",
+		},
+		{
+			name:                "with json preferred over xml",
+			accept:              "application/xml;q=0.5, application/json;q=0.8",
+			expectedContentType: "application/json; charset=utf-8",
+			expectedBody:        "{\"name\":\"golazy\"}\n",
+		},
+		{
+			name:                "with no acceptable media type",
+			accept:              "application/xml;q=0, text/html",
+			expectedContentType: "application/json; charset=utf-8",
+			expectedBody:        "{\"name\":\"golazy\"}\n",
+		},
+		{
+			name:                "with wildcard accept header",
+			accept:              "*/*",
+			expectedContentType: "application/json; charset=utf-8",
+			expectedBody:        "{\"name\":\"golazy\"}\n",
+		},
+		{
+			name:                "without accept header",
+			accept:              "",
+			expectedContentType: "application/json; charset=utf-8",
+			expectedBody:        "{\"name\":\"golazy\"}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+
+			err := Respond(rec, req, http.StatusCreated, respondPayload{Name: "golazy"})
+			assert.NilError(t, err)
+			assert.Equal(t, rec.Code, http.StatusCreated)
+			assert.Equal(t, rec.Header().Get("Content-Type"), tt.expectedContentType)
+			assert.Equal(t, rec.Body.String(), tt.expectedBody)
+		})
+	}
+
+	t.Run("should not write anything on encoding errors", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+
+		err := Respond(rec, req, http.StatusOK, make(chan int))
+		assert.ErrorContains(t, err, "cannot encode response")
+		assert.Equal(t, rec.Body.Len(), 0)
+		assert.Equal(t, rec.Header().Get("Content-Type"), "")
+	})
+}
@@ -1,7 +1,13 @@
 package utility
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
 )
 
 // UnmarshalJSONAs unmarshals a JSON byte slice into a value of type T
@@ -17,3 +23,290 @@ func UnmarshalJSONAs[T any](data []byte) (*T, error) {
 	}
 	return &v, nil
 }
+
+// UnmarshalJSONAsNumber unmarshals a JSON byte slice into a value of type T
+// like UnmarshalJSONAs, except that numbers decoded into fields of type any
+// (including within maps and slices of any) are json.Number instead of
+// float64, so that large integers such as IDs or amounts keep their precision.
+// It returns an error if data holds more than one JSON value.
+//
+// Example:
+//
+//	event, err := UnmarshalJSONAsNumber[map[string]any](jsonData)
+//	id, err := event["id"].(json.Number).Int64()
+func UnmarshalJSONAsNumber[T any](data []byte) (*T, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var v T
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	if err := expectEOF(decoder); err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+// expectEOF returns an error if decoder holds more data after the decoded
+// value. Unlike json.Decoder.More, it also rejects a trailing ']' or '}'.
+func expectEOF(decoder *json.Decoder) error {
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return errors.New("unexpected data after top-level value")
+	}
+	return nil
+}
+
+// UnmarshalJSONAsWithDefaults unmarshals a JSON byte slice into a copy of
+// defaults and returns a pointer to it: fields present in data override the
+// defaults, while absent fields keep their default value.
+//
+// The copy is shallow: maps, slices and pointers held by defaults are shared
+// with the result and may be written by the decoding (e.g. JSON objects are
+// merged into a default map). Build fresh defaults for each call when they
+// hold such values.
+//
+// Example:
+//
+//	cfg, err := UnmarshalJSONAsWithDefaults(data, Config{Port: 8080, Timeout: "5s"})
+func UnmarshalJSONAsWithDefaults[T any](data []byte, defaults T) (*T, error) {
+	v := defaults
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// DeepCopy returns an independent copy of v, obtained by marshalling it to
+// JSON and unmarshalling the result. Mutating the copy, including its nested
+// slices, maps and pointers, does not affect v.
+//
+// It only works for JSON-serializable values: unexported fields and fields
+// tagged `json:"-"` are not copied and are left to their zero value, and
+// values with custom JSON methods are copied as those methods define. It
+// returns an error if v cannot be marshalled (e.g. channels or functions).
+//
+// Example:
+//
+//	cfg, err := DeepCopy(defaultConfig)
+//	cfg.Hosts = append(cfg.Hosts, "backup")
+func DeepCopy[T any](v T) (T, error) {
+	var res T
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return res, err
+	}
+
+	if err := json.Unmarshal(data, &res); err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// EncodeJSONArray writes the elements received from items to w as a JSON array,
+// encoding and writing each element as soon as it arrives, until items is
+// closed. Memory usage is bounded by the largest element rather than the whole
+// list, which suits large API responses. An empty channel produces "[]".
+//
+// After each element, w is flushed if it implements Flush() (such as
+// http.Flusher) or Flush() error (such as *bufio.Writer), so that the client
+// receives the data incrementally.
+//
+// On error the array is left incomplete and items is no longer read: the
+// producer must be able to stop, e.g. through a context cancelled by the caller.
+//
+// Example:
+//
+//	w.Header().Set("Content-Type", "application/json")
+//	err := EncodeJSONArray(w, users)
+func EncodeJSONArray[T any](w io.Writer, items <-chan T) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+
+	first := true
+	for item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+
+		if !first {
+			data = append([]byte{','}, data...)
+		}
+		first = false
+
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+
+		if err := flush(w); err != nil {
+			return err
+		}
+	}
+
+	if _, err := io.WriteString(w, "]"); err != nil {
+		return err
+	}
+
+	return flush(w)
+}
+
+// flush flushes w if it supports flushing.
+func flush(w io.Writer) error {
+	switch f := w.(type) {
+	case interface{ Flush() error }:
+		return f.Flush()
+	case interface{ Flush() }:
+		f.Flush()
+	}
+	return nil
+}
+
+// RedactJSON replaces the values of the given fields with "***" in the JSON
+// document data and returns the redacted document, e.g. for safe logging.
+// Fields are top-level keys or dotted paths to nested keys (e.g. "user.password");
+// when a path crosses an array, it is applied to each of its elements.
+// Fields not present in data are ignored. Object keys of the returned
+// document are sorted and insignificant whitespace is removed. It returns an
+// error if data holds more than one JSON value.
+//
+// Example:
+//
+//	redacted, err := RedactJSON(body, "password", "card.number")
+func RedactJSON(data []byte, fields ...string) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var v any
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	if err := expectEOF(decoder); err != nil {
+		return nil, err
+	}
+
+	for _, f := range fields {
+		redact(v, strings.Split(f, "."))
+	}
+
+	return json.Marshal(v)
+}
+
+func redact(v any, path []string) {
+	switch t := v.(type) {
+	case map[string]any:
+		child, ok := t[path[0]]
+		if !ok {
+			return
+		}
+
+		if len(path) == 1 {
+			t[path[0]] = "***"
+			return
+		}
+
+		redact(child, path[1:])
+	case []any:
+		for _, e := range t {
+			redact(e, path)
+		}
+	}
+}
+
+// GetJSON extracts the value at the given path from the JSON document data and
+// unmarshals it as T. The path is made of dot-separated object keys and array
+// indexes (e.g. "items.0.id"); an empty path selects the whole document.
+// It returns an error if data holds more than one JSON value, if the path does
+// not exist or if the value cannot be unmarshalled as T.
+//
+// Example:
+//
+//	id, err := GetJSON[int](body, "items.0.id")
+func GetJSON[T any](data []byte, path string) (T, error) {
+	var res T
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var v any
+	if err := decoder.Decode(&v); err != nil {
+		return res, err
+	}
+
+	if err := expectEOF(decoder); err != nil {
+		return res, err
+	}
+
+	if path != "" {
+		for _, key := range strings.Split(path, ".") {
+			switch t := v.(type) {
+			case map[string]any:
+				child, ok := t[key]
+				if !ok {
+					return res, fmt.Errorf("path %q not found: missing key %q", path, key)
+				}
+				v = child
+			case []any:
+				i, err := strconv.Atoi(key)
+				if err != nil || i < 0 || i >= len(t) {
+					return res, fmt.Errorf("path %q not found: invalid index %q", path, key)
+				}
+				v = t[i]
+			default:
+				return res, fmt.Errorf("path %q not found: %q is not an object or an array", path, key)
+			}
+		}
+	}
+
+	// the value is marshalled back so that T is decoded by the standard rules
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return res, err
+	}
+
+	if err := json.Unmarshal(raw, &res); err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// CanonicalJSON returns a canonical form of the JSON document data: object keys
+// are sorted recursively, insignificant whitespace is removed and numbers are
+// kept as written. Semantically equal documents whose keys are ordered
+// differently produce identical bytes, e.g. to compute reproducible signatures.
+//
+// Example:
+//
+//	canonical, err := CanonicalJSON(payload)
+//	mac := hmac.New(sha256.New, key)
+//	mac.Write(canonical)
+func CanonicalJSON(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var v any
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	if err := expectEOF(decoder); err != nil {
+		return nil, err
+	}
+
+	// maps are always encoded with sorted keys
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
@@ -1,6 +1,10 @@
 package utility
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"math"
 	"testing"
 
 	"gotest.tools/v3/assert"
@@ -63,3 +67,435 @@ func TestUnmarshalJSONAs(t *testing.T) {
 		})
 	}
 }
+
+func TestUnmarshalJSONAsNumber(t *testing.T) {
+	t.Parallel()
+
+	type Payment struct {
+		ID       int64          `json:"id"`
+		Amount   any            `json:"amount"`
+		Metadata map[string]any `json:"metadata"`
+	}
+
+	t.Run("should keep the precision of large integers", func(t *testing.T) {
+		res, err := UnmarshalJSONAsNumber[Payment]([]byte(
+			`{"id":9007199254740993,"amount":12345678901234567.89,"metadata":{"ref":9223372036854775807}}`))
+		assert.NilError(t, err)
+
+		assert.Equal(t, res.ID, int64(9007199254740993))
+		assert.Equal(t, res.Amount, json.Number("12345678901234567.89"))
+
+		ref, err := res.Metadata["ref"].(json.Number).Int64()
+		assert.NilError(t, err)
+		assert.Equal(t, ref, int64(math.MaxInt64))
+	})
+
+	t.Run("should differ from UnmarshalJSONAs for any values", func(t *testing.T) {
+		data := []byte(`[9007199254740993]`)
+
+		res, err := UnmarshalJSONAsNumber[[]any](data)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, *res, []any{json.Number("9007199254740993")})
+
+		lossy, err := UnmarshalJSONAs[[]any](data)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, *lossy, []any{float64(9007199254740992)})
+	})
+
+	t.Run("should fail on invalid documents", func(t *testing.T) {
+		res, err := UnmarshalJSONAsNumber[Payment]([]byte(`{"id":"foo"}`))
+		assert.ErrorContains(t, err, "json: cannot unmarshal string")
+		assert.Assert(t, res == nil)
+
+		for _, data := range []string{`{"id":1} {"id":2}`, `{"id":1}]`, `{"id":1}}`, `{"id":1} ,`} {
+			res, err = UnmarshalJSONAsNumber[Payment]([]byte(data))
+			assert.ErrorContains(t, err, "unexpected data after top-level value", data)
+			assert.Assert(t, res == nil, data)
+		}
+
+		res, err = UnmarshalJSONAsNumber[Payment]([]byte("{\"id\":1}\n"))
+		assert.NilError(t, err)
+		assert.Equal(t, res.ID, int64(1))
+	})
+}
+
+func TestUnmarshalJSONAsWithDefaults(t *testing.T) {
+	t.Parallel()
+
+	type Server struct {
+		Host string `json:"host"`
+		Port int    `json:"port"`
+	}
+
+	type Config struct {
+		Name    string   `json:"name"`
+		Debug   bool     `json:"debug"`
+		Tags    []string `json:"tags"`
+		Server  Server   `json:"server"`
+		Retries int      `json:"retries"`
+	}
+
+	defaults := Config{
+		Name:    "app",
+		Debug:   true,
+		Tags:    []string{"default"},
+		Server:  Server{Host: "localhost", Port: 8080},
+		Retries: 3,
+	}
+
+	tests := []struct {
+		name        string
+		rawJSON     []byte
+		expectedRes *Config
+		expectedErr string
+	}{
+		{
+			name:        "should keep defaults for an empty object",
+			rawJSON:     []byte(`{}`),
+			expectedRes: &defaults,
+		},
+		{
+			name:    "should override present fields only",
+			rawJSON: []byte(`{"name":"api","debug":false,"tags":["a","b"],"server":{"port":9090}}`),
+			expectedRes: &Config{
+				Name:    "api",
+				Debug:   false,
+				Tags:    []string{"a", "b"},
+				Server:  Server{Host: "localhost", Port: 9090},
+				Retries: 3,
+			},
+		},
+		{
+			name:        "should fail on type mismatch",
+			rawJSON:     []byte(`{"retries":"three"}`),
+			expectedErr: "json: cannot unmarshal string",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := UnmarshalJSONAsWithDefaults(tt.rawJSON, defaults)
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				assert.Assert(t, res == nil)
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.DeepEqual(t, res, tt.expectedRes)
+		})
+	}
+
+	assert.Equal(t, defaults.Name, "app", "defaults should not be modified")
+	assert.Equal(t, defaults.Server.Port, 8080, "defaults should not be modified")
+}
+
+func TestDeepCopy(t *testing.T) {
+	t.Parallel()
+
+	type Node struct {
+		Name     string            `json:"name"`
+		Labels   map[string]string `json:"labels"`
+		Children []*Node           `json:"children"`
+	}
+
+	t.Run("should produce an independent copy", func(t *testing.T) {
+		original := Node{
+			Name:     "root",
+			Labels:   map[string]string{"env": "prod"},
+			Children: []*Node{{Name: "child", Labels: map[string]string{"a": "1"}}},
+		}
+
+		cp, err := DeepCopy(original)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, cp, original)
+
+		cp.Name = "copy"
+		cp.Labels["env"] = "dev"
+		cp.Children[0].Name = "changed"
+		cp.Children[0].Labels["a"] = "2"
+		cp.Children = append(cp.Children, &Node{Name: "new"})
+
+		assert.Equal(t, original.Name, "root")
+		assert.Equal(t, original.Labels["env"], "prod")
+		assert.Equal(t, original.Children[0].Name, "child")
+		assert.Equal(t, original.Children[0].Labels["a"], "1")
+		assert.Equal(t, len(original.Children), 1)
+	})
+
+	t.Run("should drop unexported fields", func(t *testing.T) {
+		type withHidden struct {
+			Name   string `json:"name"`
+			hidden string
+		}
+
+		cp, err := DeepCopy(withHidden{Name: "root", hidden: "secret"})
+		assert.NilError(t, err)
+		assert.Equal(t, cp.Name, "root")
+		assert.Equal(t, cp.hidden, "")
+	})
+
+	t.Run("should fail on non serializable values", func(t *testing.T) {
+		_, err := DeepCopy(map[string]any{"ch": make(chan int)})
+		assert.ErrorContains(t, err, "json: unsupported type")
+	})
+}
+
+// flushRecorder records the content written to it at each flush.
+type flushRecorder struct {
+	bytes.Buffer
+	flushes []string
+}
+
+func (f *flushRecorder) Flush() {
+	f.flushes = append(f.flushes, f.String())
+}
+
+func TestEncodeJSONArray(t *testing.T) {
+	t.Parallel()
+
+	type Item struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	send := func(items ...Item) <-chan Item {
+		ch := make(chan Item, len(items))
+		for _, item := range items {
+			ch <- item
+		}
+		close(ch)
+		return ch
+	}
+
+	tests := []struct {
+		name     string
+		items    []Item
+		expected string
+	}{
+		{name: "with an empty channel", items: nil, expected: `[]`},
+		{name: "with a single element", items: []Item{{ID: 1, Name: "foo"}}, expected: `[{"id":1,"name":"foo"}]`},
+		{
+			name:     "with several elements",
+			items:    []Item{{ID: 1, Name: "foo"}, {ID: 2, Name: "bar"}, {ID: 3, Name: "baz"}},
+			expected: `[{"id":1,"name":"foo"},{"id":2,"name":"bar"},{"id":3,"name":"baz"}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			assert.NilError(t, EncodeJSONArray(&buf, send(tt.items...)))
+			assert.Equal(t, buf.String(), tt.expected)
+
+			var decoded []Item
+			assert.NilError(t, json.Unmarshal(buf.Bytes(), &decoded))
+			assert.Equal(t, len(decoded), len(tt.items))
+		})
+	}
+
+	t.Run("should flush after each element", func(t *testing.T) {
+		w := &flushRecorder{}
+		assert.NilError(t, EncodeJSONArray(w, send(Item{ID: 1}, Item{ID: 2})))
+		assert.DeepEqual(t, w.flushes, []string{
+			`[{"id":1,"name":""}`,
+			`[{"id":1,"name":""},{"id":2,"name":""}`,
+			`[{"id":1,"name":""},{"id":2,"name":""}]`,
+		})
+	})
+
+	t.Run("should flush a bufio writer", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NilError(t, EncodeJSONArray(bufio.NewWriter(&buf), send(Item{ID: 1})))
+		assert.Equal(t, buf.String(), `[{"id":1,"name":""}]`)
+	})
+
+	t.Run("should fail on unsupported values", func(t *testing.T) {
+		ch := make(chan any, 1)
+		ch <- func() {}
+		close(ch)
+
+		var buf bytes.Buffer
+		err := EncodeJSONArray(&buf, ch)
+		assert.ErrorContains(t, err, "json: unsupported type")
+		assert.Equal(t, buf.String(), "[")
+	})
+}
+
+func TestRedactJSON(t *testing.T) {
+	t.Parallel()
+
+	type input struct {
+		rawJSON []byte
+		fields  []string
+	}
+
+	type output struct {
+		expectedRes string
+		expectedErr string
+	}
+
+	tests := []struct {
+		name   string
+		input  input
+		output output
+	}{
+		{
+			name: "should redact top-level fields",
+			input: input{
+				rawJSON: []byte(`{"user":"foo","password":"secret","token":123}`),
+				fields:  []string{"password", "token"},
+			},
+			output: output{
+				expectedRes: `{"password":"***","token":"***","user":"foo"}`,
+			},
+		},
+		{
+			name: "should redact nested fields",
+			input: input{
+				rawJSON: []byte(`{"user":{"name":"foo","password":"secret"},"cards":[{"number":"4111","cvv":"123"},{"number":"5500"}]}`),
+				fields:  []string{"user.password", "cards.number"},
+			},
+			output: output{
+				expectedRes: `{"cards":[{"cvv":"123","number":"***"},{"number":"***"}],"user":{"name":"foo","password":"***"}}`,
+			},
+		},
+		{
+			name: "should ignore missing fields and preserve numbers",
+			input: input{
+				rawJSON: []byte(`{"amount":12345678901234567890,"nested":"value"}`),
+				fields:  []string{"password", "nested.password"},
+			},
+			output: output{
+				expectedRes: `{"amount":12345678901234567890,"nested":"value"}`,
+			},
+		},
+		{
+			name: "should fail on invalid JSON",
+			input: input{
+				rawJSON: []byte(`{"password":`),
+				fields:  []string{"password"},
+			},
+			output: output{
+				expectedErr: "unexpected EOF",
+			},
+		},
+		{
+			name: "should fail on trailing data",
+			input: input{
+				rawJSON: []byte(`{"password":"x"} {"password":"y"}`),
+				fields:  []string{"password"},
+			},
+			output: output{
+				expectedErr: "unexpected data after top-level value",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := RedactJSON(tt.input.rawJSON, tt.input.fields...)
+			if tt.output.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.output.expectedErr)
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.Equal(t, string(res), tt.output.expectedRes)
+		})
+	}
+}
+
+func TestGetJSON(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`{"name":"foo","big":9007199254740993,"user":{"id":42,"tags":["a","b"]},"items":[{"id":1},{"id":2,"price":9.5}]}`)
+
+	t.Run("should get nested object field", func(t *testing.T) {
+		res, err := GetJSON[int](data, "user.id")
+		assert.NilError(t, err)
+		assert.Equal(t, res, 42)
+
+		big, err := GetJSON[int64](data, "big")
+		assert.NilError(t, err)
+		assert.Equal(t, big, int64(9007199254740993))
+	})
+
+	t.Run("should get array element", func(t *testing.T) {
+		res, err := GetJSON[float64](data, "items.1.price")
+		assert.NilError(t, err)
+		assert.Equal(t, res, 9.5)
+
+		tag, err := GetJSON[string](data, "user.tags.1")
+		assert.NilError(t, err)
+		assert.Equal(t, tag, "b")
+	})
+
+	t.Run("should get composite values", func(t *testing.T) {
+		type item struct {
+			ID int `json:"id"`
+		}
+
+		res, err := GetJSON[[]item](data, "items")
+		assert.NilError(t, err)
+		assert.DeepEqual(t, res, []item{{ID: 1}, {ID: 2}})
+	})
+
+	t.Run("should fail on missing paths", func(t *testing.T) {
+		_, err := GetJSON[int](data, "user.missing")
+		assert.ErrorContains(t, err, `path "user.missing" not found: missing key "missing"`)
+
+		_, err = GetJSON[int](data, "items.5.id")
+		assert.ErrorContains(t, err, `path "items.5.id" not found: invalid index "5"`)
+
+		_, err = GetJSON[int](data, "name.id")
+		assert.ErrorContains(t, err, `path "name.id" not found: "id" is not an object or an array`)
+	})
+
+	t.Run("should fail on type mismatch", func(t *testing.T) {
+		_, err := GetJSON[int](data, "name")
+		assert.ErrorContains(t, err, "json: cannot unmarshal string")
+	})
+
+	t.Run("should fail on trailing data", func(t *testing.T) {
+		for _, raw := range []string{`{"id":1}]`, `{"id":1} {"id":2}`} {
+			_, err := GetJSON[int]([]byte(raw), "id")
+			assert.ErrorContains(t, err, "unexpected data after top-level value", raw)
+		}
+	})
+}
+
+func TestCanonicalJSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should produce identical bytes for differently ordered inputs", func(t *testing.T) {
+		a, err := CanonicalJSON([]byte(`{"b":1,"a":{"y":[3,{"k":2,"j":1}],"x":"<&>"}}`))
+		assert.NilError(t, err)
+
+		b, err := CanonicalJSON([]byte(`
+			{
+				"a": {"x": "<&>", "y": [3, {"j": 1, "k": 2}]},
+				"b": 1
+			}`))
+		assert.NilError(t, err)
+
+		assert.Equal(t, string(a), `{"a":{"x":"<&>","y":[3,{"j":1,"k":2}]},"b":1}`)
+		assert.DeepEqual(t, a, b)
+	})
+
+	t.Run("should keep numbers as written", func(t *testing.T) {
+		res, err := CanonicalJSON([]byte(`{"n":12345678901234567890,"f":1.50}`))
+		assert.NilError(t, err)
+		assert.Equal(t, string(res), `{"f":1.50,"n":12345678901234567890}`)
+	})
+
+	t.Run("should fail on invalid JSON", func(t *testing.T) {
+		for _, data := range []string{`{"a":1} {"b":2}`, `{"a":1}]`, `{"a":1}}`} {
+			_, err := CanonicalJSON([]byte(data))
+			assert.ErrorContains(t, err, "unexpected data after top-level value", data)
+		}
+
+		_, err := CanonicalJSON([]byte(`{"a":`))
+		assert.ErrorContains(t, err, "unexpected EOF")
+	})
+}
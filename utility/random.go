@@ -0,0 +1,67 @@
+package utility
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+)
+
+// base62Alphabet is the set of characters used by RandomID.
+const base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// RandomID returns a cryptographically random, URL-safe string of the given
+// length made of base62 characters ([0-9A-Za-z]). Characters are uniformly
+// distributed.
+//
+// Example:
+//
+//	id, err := RandomID(12) // e.g. "4fG9xQ2mZk0B"
+func RandomID(length int) (string, error) {
+	if length < 0 {
+		return "", errors.New("length must be >= 0")
+	}
+
+	// bytes >= 248 are discarded so that each character is picked
+	// with the same probability (248 is the largest multiple of 62 <= 256)
+	const maxByte = 256 - 256%len(base62Alphabet)
+
+	id := make([]byte, 0, length)
+	buf := make([]byte, length+length/4+1)
+	for len(id) < length {
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+
+		for _, b := range buf {
+			if int(b) >= maxByte {
+				continue
+			}
+
+			id = append(id, base62Alphabet[int(b)%len(base62Alphabet)])
+			if len(id) == length {
+				break
+			}
+		}
+	}
+
+	return string(id), nil
+}
+
+// RandomBase32 returns the unpadded base32 encoding ([A-Z2-7]) of the given
+// number of cryptographically random bytes.
+//
+// Example:
+//
+//	id, err := RandomBase32(10) // 16 characters, e.g. "MZXW6YTBOI3TQOJA"
+func RandomBase32(bytes int) (string, error) {
+	if bytes < 0 {
+		return "", errors.New("bytes must be >= 0")
+	}
+
+	buf := make([]byte, bytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(buf), nil
+}
@@ -0,0 +1,60 @@
+package utility
+
+import (
+	"regexp"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestRandomID(t *testing.T) {
+	t.Parallel()
+
+	charset := regexp.MustCompile(`^[0-9A-Za-z]*$`)
+
+	for _, length := range []int{0, 1, 12, 64} {
+		id, err := RandomID(length)
+		assert.NilError(t, err)
+		assert.Equal(t, len(id), length)
+		assert.Assert(t, charset.MatchString(id), "unexpected character in %q", id)
+	}
+
+	seen := make(map[string]struct{}, 10000)
+	for range 10000 {
+		id, err := RandomID(16)
+		assert.NilError(t, err)
+
+		_, ok := seen[id]
+		assert.Assert(t, !ok, "duplicated id %q", id)
+		seen[id] = struct{}{}
+	}
+
+	_, err := RandomID(-1)
+	assert.ErrorContains(t, err, "length must be >= 0")
+}
+
+func TestRandomBase32(t *testing.T) {
+	t.Parallel()
+
+	charset := regexp.MustCompile(`^[A-Z2-7]*$`)
+
+	for bytes, length := range map[int]int{0: 0, 5: 8, 10: 16, 16: 26} {
+		id, err := RandomBase32(bytes)
+		assert.NilError(t, err)
+		assert.Equal(t, len(id), length)
+		assert.Assert(t, charset.MatchString(id), "unexpected character in %q", id)
+	}
+
+	seen := make(map[string]struct{}, 10000)
+	for range 10000 {
+		id, err := RandomBase32(10)
+		assert.NilError(t, err)
+
+		_, ok := seen[id]
+		assert.Assert(t, !ok, "duplicated id %q", id)
+		seen[id] = struct{}{}
+	}
+
+	_, err := RandomBase32(-1)
+	assert.ErrorContains(t, err, "bytes must be >= 0")
+}
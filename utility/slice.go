@@ -1,5 +1,18 @@
 package utility
 
+import (
+	"cmp"
+	"context"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// mapConcurrentThreshold is the input length below which MapConcurrent maps
+// sequentially, as starting goroutines costs more than it saves for tiny slices.
+const mapConcurrentThreshold = 16
+
 // Map returns a new slice of []b from slice []a.
 func Map[A any, B any](input []A, f func(A) B) []B {
 	output, _ := mapInternal(input, func(a A) (B, error) {
@@ -18,6 +31,121 @@ func MapE[A any, B any](input []A, f func(A) (B, error)) ([]B, error) {
 	return s, nil
 }
 
+// MapConcurrent returns a new slice of []b from slice []a, calling f from at
+// most concurrency goroutines. The output order matches the input order.
+// A concurrency lower than or equal to zero defaults to runtime.GOMAXPROCS(0).
+// Inputs shorter than 16 elements, or a concurrency of 1, are mapped
+// sequentially, avoiding the goroutines overhead. f must be safe for
+// concurrent use.
+func MapConcurrent[A any, B any](input []A, concurrency int, f func(A) B) []B {
+	if concurrency <= 0 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+
+	if concurrency == 1 || len(input) < mapConcurrentThreshold {
+		return Map(input, f)
+	}
+
+	return mapConcurrent(input, concurrency, f)
+}
+
+// mapConcurrent maps input with concurrency workers, each picking the next
+// unprocessed index until all the elements are mapped.
+func mapConcurrent[A any, B any](input []A, concurrency int, f func(A) B) []B {
+	output := make([]B, len(input))
+
+	var (
+		wg   sync.WaitGroup
+		next atomic.Int64
+	)
+	for range min(concurrency, len(input)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= len(input) {
+					return
+				}
+				output[i] = f(input[i])
+			}
+		}()
+	}
+	wg.Wait()
+
+	return output
+}
+
+// ParallelMap returns a new slice of []b from slice []a, calling f from at
+// most concurrency goroutines. The output order matches the input order.
+// A concurrency lower than or equal to zero defaults to len(input). It returns
+// the first error returned by f with a nil slice: once an error occurs, the
+// remaining elements are no longer mapped, while the calls already running
+// are waited for. f must be safe for concurrent use.
+func ParallelMap[A any, B any](input []A, concurrency int, f func(A) (B, error)) ([]B, error) {
+	if concurrency <= 0 {
+		concurrency = max(len(input), 1)
+	}
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+
+	output := make([]B, len(input))
+
+	var (
+		wg   sync.WaitGroup
+		next atomic.Int64
+	)
+	for range min(concurrency, len(input)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				i := int(next.Add(1) - 1)
+				if i >= len(input) {
+					return
+				}
+
+				mapped, err := f(input[i])
+				if err != nil {
+					cancel(err)
+					return
+				}
+				output[i] = mapped
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := context.Cause(ctx); err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+// MapCollect returns a new slice of []b from slice []a, applying f to every
+// element without stopping at the first error. Both returned slices have the
+// same length as input: errs[i] holds the error returned for input[i], in which
+// case output[i] is the zero value. errs is nil when every call succeeds.
+// Use errors.Join(errs...) to obtain a single error.
+func MapCollect[A any, B any](input []A, f func(A) (B, error)) ([]B, []error) {
+	output := make([]B, len(input))
+	var errs []error
+	for i, v := range input {
+		mapped, err := f(v)
+		if err != nil {
+			if errs == nil {
+				errs = make([]error, len(input))
+			}
+			errs[i] = err
+			continue
+		}
+		output[i] = mapped
+	}
+	return output, errs
+}
+
 func mapInternal[A any, B any](input []A, f func(A) (B, error)) ([]B, error) {
 	output := make([]B, len(input))
 	for i, v := range input {
@@ -29,3 +157,431 @@ func mapInternal[A any, B any](input []A, f func(A) (B, error)) ([]B, error) {
 	}
 	return output, nil
 }
+
+// FlatMap returns a new slice concatenating the slices returned by f for each
+// element of input, in order. It never returns nil.
+func FlatMap[A any, B any](input []A, f func(A) []B) []B {
+	output := make([]B, 0, len(input))
+	for _, v := range input {
+		output = append(output, f(v)...)
+	}
+	return output
+}
+
+// Flatten returns a new slice concatenating all the slices of input, in order.
+// It never returns nil.
+//
+// Example:
+//
+//	Flatten([][]int{{1, 2}, {}, {3}}) // [1 2 3]
+func Flatten[T any](input [][]T) []T {
+	total := 0
+	for _, s := range input {
+		total += len(s)
+	}
+
+	output := make([]T, 0, total)
+	for _, s := range input {
+		output = append(output, s...)
+	}
+	return output
+}
+
+// MapFilter returns a new slice of []b from slice []a, keeping only the elements
+// for which f returns true as second value. The order of the kept elements is preserved.
+func MapFilter[A any, B any](input []A, f func(A) (B, bool)) []B {
+	output := make([]B, 0, len(input))
+	for _, v := range input {
+		if mapped, ok := f(v); ok {
+			output = append(output, mapped)
+		}
+	}
+	return output
+}
+
+// Filter returns a new slice containing only the elements of input for which
+// keep returns true, preserving their order. It never returns nil: when no
+// element is kept, the result is an empty slice.
+func Filter[T any](input []T, keep func(T) bool) []T {
+	output, _ := FilterE(input, func(v T) (bool, error) {
+		return keep(v), nil
+	})
+	return output
+}
+
+// FilterE returns a new slice containing only the elements of input for which
+// keep returns true, preserving their order. It stops at the first error
+// returned by keep and returns it with a nil slice. On success the result is
+// never nil: when no element is kept, it is an empty slice.
+func FilterE[T any](input []T, keep func(T) (bool, error)) ([]T, error) {
+	output := make([]T, 0, len(input))
+	for _, v := range input {
+		ok, err := keep(v)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			output = append(output, v)
+		}
+	}
+	return output, nil
+}
+
+// Partition splits input into the elements for which pred returns true and
+// the rest, iterating once and preserving their order in both slices. Neither
+// of the returned slices is nil: a side without elements is an empty slice.
+func Partition[T any](input []T, pred func(T) bool) (matched []T, rest []T) {
+	matched, rest = make([]T, 0, len(input)), make([]T, 0, len(input))
+	for _, v := range input {
+		if pred(v) {
+			matched = append(matched, v)
+		} else {
+			rest = append(rest, v)
+		}
+	}
+	return matched, rest
+}
+
+// Reduce folds input from left to right, calling f with the accumulator and
+// each element in turn, starting from initial. It returns initial when input
+// is empty.
+//
+// Example:
+//
+//	Reduce([]int{1, 2, 3}, 0, func(acc, n int) int { return acc + n }) // 6
+func Reduce[T any, A any](input []T, initial A, f func(acc A, item T) A) A {
+	acc, _ := ReduceE(input, initial, func(acc A, item T) (A, error) {
+		return f(acc, item), nil
+	})
+	return acc
+}
+
+// ReduceE folds input from left to right like Reduce. It stops at the first
+// error returned by f and returns it with the zero value of the accumulator.
+func ReduceE[T any, A any](input []T, initial A, f func(acc A, item T) (A, error)) (A, error) {
+	acc := initial
+	for _, v := range input {
+		var err error
+		acc, err = f(acc, v)
+		if err != nil {
+			var zero A
+			return zero, err
+		}
+	}
+	return acc, nil
+}
+
+// Windows returns all the contiguous sub-slices of s of length size, in order.
+// If s is shorter than size, or size is not positive, it returns an empty result.
+// Each window is a copy, so modifying a window does not affect s or the other windows.
+func Windows[T any](s []T, size int) [][]T {
+	if size <= 0 || len(s) < size {
+		return [][]T{}
+	}
+
+	output := make([][]T, 0, len(s)-size+1)
+	for i := 0; i+size <= len(s); i++ {
+		output = append(output, slices.Clone(s[i:i+size]))
+	}
+	return output
+}
+
+// Chunk splits input into consecutive sub-slices of at most size elements, in
+// order: all the chunks hold exactly size elements except the last one, which
+// holds the remainder. If input is empty, or size is not positive, it returns
+// an empty result. Each chunk is a copy, so modifying a chunk does not affect input.
+//
+// Example:
+//
+//	Chunk([]int{1, 2, 3, 4, 5}, 2) // [[1 2] [3 4] [5]]
+func Chunk[T any](input []T, size int) [][]T {
+	if size <= 0 || len(input) == 0 {
+		return [][]T{}
+	}
+
+	output := make([][]T, 0, (len(input)+size-1)/size)
+	for i := 0; i < len(input); i += size {
+		output = append(output, slices.Clone(input[i:min(i+size, len(input))]))
+	}
+	return output
+}
+
+// Interleave merges the given slices taking one element from each of them in turn
+// (round-robin) until all of them are exhausted. Shorter slices simply drop out.
+//
+// Example:
+//
+//	Interleave([]int{1, 2, 3}, []int{10}, []int{100, 200}) // [1 10 100 2 200 3]
+func Interleave[T any](input ...[]T) []T {
+	total, longest := 0, 0
+	for _, s := range input {
+		total += len(s)
+		longest = max(longest, len(s))
+	}
+
+	output := make([]T, 0, total)
+	for i := range longest {
+		for _, s := range input {
+			if i < len(s) {
+				output = append(output, s[i])
+			}
+		}
+	}
+	return output
+}
+
+// Unique returns a new slice with the duplicate elements of input removed,
+// keeping the first occurrence of each element in order. It never returns
+// nil: for an empty input, the result is an empty slice.
+func Unique[T comparable](input []T) []T {
+	return UniqueBy(input, func(v T) T { return v })
+}
+
+// UniqueBy returns a new slice with the elements of input whose key is a
+// duplicate removed, keeping the first element for each key in order. It never
+// returns nil: for an empty input, the result is an empty slice.
+func UniqueBy[T any, K comparable](input []T, key func(T) K) []T {
+	output := make([]T, 0, len(input))
+	seen := make(map[K]struct{}, len(input))
+	for _, v := range input {
+		k := key(v)
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+		output = append(output, v)
+	}
+	return output
+}
+
+// Union returns the distinct elements of a and b, in order of first
+// appearance across a then b. It never returns nil.
+func Union[T comparable](a, b []T) []T {
+	output := make([]T, 0, len(a)+len(b))
+	output = append(output, a...)
+	return Unique(append(output, b...))
+}
+
+// Intersection returns the distinct elements of a that are also in b, in the
+// order of a. It never returns nil.
+func Intersection[T comparable](a, b []T) []T {
+	inB := toSet(b)
+	return Unique(Filter(a, func(v T) bool {
+		_, ok := inB[v]
+		return ok
+	}))
+}
+
+// Difference returns the distinct elements of a that are not in b, in the
+// order of a. It never returns nil.
+func Difference[T comparable](a, b []T) []T {
+	inB := toSet(b)
+	return Unique(Filter(a, func(v T) bool {
+		_, ok := inB[v]
+		return !ok
+	}))
+}
+
+// toSet returns the set of the elements of input.
+func toSet[T comparable](input []T) map[T]struct{} {
+	set := make(map[T]struct{}, len(input))
+	for _, v := range input {
+		set[v] = struct{}{}
+	}
+	return set
+}
+
+// GroupBy groups the elements of input by the key returned by key. Within
+// each group, elements keep their order in input. It never returns nil: for
+// an empty input, the result is an empty map.
+//
+// Example:
+//
+//	GroupBy(users, func(u User) string { return u.Role }) // map[admin:[...] editor:[...]]
+func GroupBy[T any, K comparable](input []T, key func(T) K) map[K][]T {
+	output := make(map[K][]T)
+	for _, v := range input {
+		k := key(v)
+		output[k] = append(output[k], v)
+	}
+	return output
+}
+
+// Contains reports whether target is present in input.
+func Contains[T comparable](input []T, target T) bool {
+	return IndexOf(input, target) >= 0
+}
+
+// IndexOf returns the index of the first occurrence of target in input,
+// or -1 if it is not present.
+func IndexOf[T comparable](input []T, target T) int {
+	for i, v := range input {
+		if v == target {
+			return i
+		}
+	}
+	return -1
+}
+
+// Find returns the first element of input for which pred returns true.
+// It returns the zero value and false when no element matches.
+func Find[T any](input []T, pred func(T) bool) (T, bool) {
+	for _, v := range input {
+		if pred(v) {
+			return v, true
+		}
+	}
+
+	var zero T
+	return zero, false
+}
+
+// ToMap returns a map built from the key and value that f derives from each
+// element of input. When several elements share a key, the last one wins.
+// It never returns nil: for an empty input, the result is an empty map.
+//
+// Example:
+//
+//	ToMap(users, func(u User) (int, string) { return u.ID, u.Name }) // map[1:alice 2:bob]
+func ToMap[T any, K comparable, V any](input []T, f func(T) (K, V)) map[K]V {
+	output := make(map[K]V, len(input))
+	for _, v := range input {
+		k, mapped := f(v)
+		output[k] = mapped
+	}
+	return output
+}
+
+// KeyBy returns a map of the elements of input by the key returned by key.
+// When several elements share a key, the last one wins. It never returns nil:
+// for an empty input, the result is an empty map.
+func KeyBy[T any, K comparable](input []T, key func(T) K) map[K]T {
+	return ToMap(input, func(v T) (K, T) {
+		return key(v), v
+	})
+}
+
+// Reverse reverses the elements of input in place.
+func Reverse[T any](input []T) {
+	slices.Reverse(input)
+}
+
+// Reversed returns a new slice holding the elements of input in reverse
+// order, leaving input untouched. It never returns nil.
+func Reversed[T any](input []T) []T {
+	output := make([]T, len(input))
+	for i, v := range input {
+		output[len(input)-1-i] = v
+	}
+	return output
+}
+
+// Number is a constraint permitting any integer or floating-point type.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Sum returns the sum of the elements of input, or 0 if it is empty.
+func Sum[T Number](input []T) T {
+	var sum T
+	for _, v := range input {
+		sum += v
+	}
+	return sum
+}
+
+// Min returns the minimal element of input. It returns the zero value and
+// false if input is empty. Floating-point NaNs are ordered as by cmp.Compare,
+// i.e. before any other value.
+func Min[T cmp.Ordered](input []T) (T, bool) {
+	return MinBy(input, func(v T) T { return v })
+}
+
+// Max returns the maximal element of input. It returns the zero value and
+// false if input is empty. Floating-point NaNs are ordered as by cmp.Compare,
+// i.e. before any other value.
+func Max[T cmp.Ordered](input []T) (T, bool) {
+	return MaxBy(input, func(v T) T { return v })
+}
+
+// MinBy returns the element of input with the minimal key, the first one in
+// case of ties. It returns the zero value and false if input is empty.
+//
+// Example:
+//
+//	youngest, ok := MinBy(users, func(u User) int { return u.Age })
+func MinBy[T any, K cmp.Ordered](input []T, key func(T) K) (T, bool) {
+	return extremeBy(input, key, -1)
+}
+
+// MaxBy returns the element of input with the maximal key, the first one in
+// case of ties. It returns the zero value and false if input is empty.
+func MaxBy[T any, K cmp.Ordered](input []T, key func(T) K) (T, bool) {
+	return extremeBy(input, key, 1)
+}
+
+// extremeBy returns the first element of input whose key compares to the keys
+// of all the other elements as sign or 0.
+func extremeBy[T any, K cmp.Ordered](input []T, key func(T) K, sign int) (T, bool) {
+	if len(input) == 0 {
+		var zero T
+		return zero, false
+	}
+
+	best, bestKey := input[0], key(input[0])
+	for _, v := range input[1:] {
+		if k := key(v); cmp.Compare(k, bestKey) == sign {
+			best, bestKey = v, k
+		}
+	}
+	return best, true
+}
+
+// Remove returns a new slice with all the elements equal to target removed.
+func Remove[T comparable](s []T, target T) []T {
+	output := make([]T, 0, len(s))
+	for _, v := range s {
+		if v != target {
+			output = append(output, v)
+		}
+	}
+	return output
+}
+
+// RemoveAt returns a new slice with the element at index removed.
+// If index is out of range, it returns a copy of s.
+func RemoveAt[T any](s []T, index int) []T {
+	if index < 0 || index >= len(s) {
+		return slices.Clone(s)
+	}
+
+	output := make([]T, 0, len(s)-1)
+	output = append(output, s[:index]...)
+	return append(output, s[index+1:]...)
+}
+
+// SliceEqual reports whether a and b have the same length and contain
+// the same elements in the same order.
+func SliceEqual[T comparable](a, b []T) bool {
+	return SliceEqualFunc(a, b, func(x, y T) bool {
+		return x == y
+	})
+}
+
+// SliceEqualFunc reports whether a and b have the same length and eq returns
+// true for each pair of elements at the same position. It is useful for
+// elements that are not comparable.
+func SliceEqualFunc[T any](a, b []T, eq func(T, T) bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if !eq(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -1,7 +1,11 @@
 package utility
 
 import (
+	"errors"
+	"runtime"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
 
 	"gotest.tools/v3/assert"
@@ -18,6 +22,138 @@ func TestMap(t *testing.T) {
 	assert.DeepEqual(t, []string{"1", "2", "3", "4", "5"}, stringNumbers)
 }
 
+func TestMapConcurrent(t *testing.T) {
+	t.Parallel()
+
+	square := func(n int) int { return n * n }
+
+	tests := []struct {
+		name        string
+		size        int
+		concurrency int
+	}{
+		{name: "empty input", size: 0, concurrency: 4},
+		{name: "small input on the sequential path", size: mapConcurrentThreshold - 1, concurrency: 4},
+		{name: "concurrency of one on the sequential path", size: 100, concurrency: 1},
+		{name: "large input on the concurrent path", size: 1000, concurrency: 8},
+		{name: "more workers than elements", size: mapConcurrentThreshold, concurrency: 64},
+		{name: "default concurrency", size: 1000, concurrency: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			input := make([]int, tt.size)
+			for i := range input {
+				input[i] = i
+			}
+
+			expected := Map(input, square)
+			assert.DeepEqual(t, MapConcurrent(input, tt.concurrency, square), expected)
+			assert.DeepEqual(t, mapConcurrent(input, max(tt.concurrency, 1), square), expected)
+		})
+	}
+}
+
+// BenchmarkMapConcurrent compares the sequential and the concurrent paths
+// for increasing input lengths, showing where the goroutines pay off.
+func BenchmarkMapConcurrent(b *testing.B) {
+	work := func(n int) int {
+		for range 200 {
+			n = n*31 + 7
+		}
+		return n
+	}
+
+	for _, size := range []int{4, 16, 64, 1024} {
+		input := make([]int, size)
+
+		b.Run("sequential/"+strconv.Itoa(size), func(b *testing.B) {
+			for b.Loop() {
+				Map(input, work)
+			}
+		})
+
+		b.Run("concurrent/"+strconv.Itoa(size), func(b *testing.B) {
+			for b.Loop() {
+				mapConcurrent(input, runtime.GOMAXPROCS(0), work)
+			}
+		})
+	}
+}
+
+func TestParallelMap(t *testing.T) {
+	t.Parallel()
+
+	square := func(n int) (int, error) { return n * n, nil }
+
+	tests := []struct {
+		name        string
+		size        int
+		concurrency int
+	}{
+		{name: "empty input", size: 0, concurrency: 4},
+		{name: "empty input with default concurrency", size: 0, concurrency: 0},
+		{name: "single worker", size: 50, concurrency: 1},
+		{name: "bounded workers", size: 1000, concurrency: 8},
+		{name: "more workers than elements", size: 10, concurrency: 64},
+		{name: "default concurrency", size: 100, concurrency: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			input := make([]int, tt.size)
+			for i := range input {
+				input[i] = i
+			}
+
+			res, err := ParallelMap(input, tt.concurrency, square)
+			assert.NilError(t, err)
+			expected, _ := MapE(input, square)
+			assert.DeepEqual(t, res, expected)
+		})
+	}
+
+	t.Run("should not exceed the concurrency", func(t *testing.T) {
+		t.Parallel()
+
+		var running, peak atomic.Int64
+		_, err := ParallelMap(make([]int, 200), 3, func(n int) (int, error) {
+			cur := running.Add(1)
+			for {
+				p := peak.Load()
+				if cur <= p || peak.CompareAndSwap(p, cur) {
+					break
+				}
+			}
+			runtime.Gosched()
+			running.Add(-1)
+			return n, nil
+		})
+		assert.NilError(t, err)
+		assert.Assert(t, peak.Load() <= 3)
+	})
+
+	t.Run("should return the first error and stop mapping", func(t *testing.T) {
+		t.Parallel()
+
+		errBoom := errors.New("boom")
+		var calls atomic.Int64
+		res, err := ParallelMap(make([]int, 1000), 2, func(n int) (int, error) {
+			if calls.Add(1) == 5 {
+				return 0, errBoom
+			}
+			return n, nil
+		})
+		assert.ErrorIs(t, err, errBoom)
+		assert.Assert(t, res == nil)
+		assert.Assert(t, calls.Load() < 1000)
+	})
+}
+
 func TestMapE(t *testing.T) {
 	t.Parallel()
 
@@ -69,3 +205,810 @@ func TestMapE(t *testing.T) {
 		})
 	}
 }
+
+func TestMapCollect(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		data           []string
+		expectedRes    []int
+		expectedErrIdx []int
+	}{
+		{
+			name:        "without errors",
+			data:        []string{"1", "2", "3"},
+			expectedRes: []int{1, 2, 3},
+		},
+		{
+			name:           "with multiple errors",
+			data:           []string{"1", "NaN", "3", "foo"},
+			expectedRes:    []int{1, 0, 3, 0},
+			expectedErrIdx: []int{1, 3},
+		},
+		{
+			name:        "with empty input",
+			data:        []string{},
+			expectedRes: []int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			numbers, errs := MapCollect(tt.data, strconv.Atoi)
+			assert.DeepEqual(t, numbers, tt.expectedRes)
+
+			if tt.expectedErrIdx == nil {
+				assert.Assert(t, errs == nil)
+				return
+			}
+
+			assert.Equal(t, len(errs), len(tt.data))
+			var idx []int
+			for i, err := range errs {
+				if err != nil {
+					assert.ErrorContains(t, err, tt.data[i])
+					idx = append(idx, i)
+				}
+			}
+			assert.DeepEqual(t, idx, tt.expectedErrIdx)
+		})
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    [][]int
+		expected []int
+	}{
+		{name: "should concatenate in order", input: [][]int{{1, 2}, {}, {3}, nil, {4, 5}}, expected: []int{1, 2, 3, 4, 5}},
+		{name: "should return an empty slice for empty inner slices", input: [][]int{{}, nil}, expected: []int{}},
+		{name: "should return an empty slice for nil input", input: nil, expected: []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := Flatten(tt.input)
+			assert.Assert(t, res != nil)
+			assert.DeepEqual(t, res, tt.expected)
+			assert.Equal(t, cap(res), len(tt.expected))
+		})
+	}
+}
+
+func TestFlatMap(t *testing.T) {
+	t.Parallel()
+
+	res := FlatMap([]string{"a,b", "", "c"}, func(s string) []string {
+		if s == "" {
+			return nil
+		}
+		return strings.Split(s, ",")
+	})
+	assert.DeepEqual(t, res, []string{"a", "b", "c"})
+
+	empty := FlatMap(nil, func(s string) []string { return []string{s} })
+	assert.Assert(t, empty != nil)
+	assert.Equal(t, len(empty), 0)
+}
+
+func TestMapFilter(t *testing.T) {
+	t.Parallel()
+
+	data := []string{"1", "foo", "3", "", "5"}
+	numbers := MapFilter(data, func(s string) (int, bool) {
+		n, err := strconv.Atoi(s)
+		return n, err == nil
+	})
+
+	assert.DeepEqual(t, []int{1, 3, 5}, numbers)
+
+	empty := MapFilter([]string{"foo"}, func(_ string) (int, bool) {
+		return 0, false
+	})
+
+	assert.DeepEqual(t, []int{}, empty)
+}
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+
+	even := func(n int) bool { return n%2 == 0 }
+
+	tests := []struct {
+		name     string
+		input    []int
+		expected []int
+	}{
+		{name: "should keep matching elements in order", input: []int{5, 2, 8, 3, 4}, expected: []int{2, 8, 4}},
+		{name: "should return an empty slice when nothing matches", input: []int{1, 3, 5}, expected: []int{}},
+		{name: "should return an empty slice for nil input", input: nil, expected: []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := Filter(tt.input, even)
+			assert.Assert(t, res != nil)
+			assert.DeepEqual(t, res, tt.expected)
+		})
+	}
+}
+
+func TestFilterE(t *testing.T) {
+	t.Parallel()
+
+	positive := func(s string) (bool, error) {
+		n, err := strconv.Atoi(s)
+		return n > 0, err
+	}
+
+	t.Run("should keep matching elements in order", func(t *testing.T) {
+		res, err := FilterE([]string{"1", "-2", "3"}, positive)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, res, []string{"1", "3"})
+
+		res, err = FilterE([]string{"-1"}, positive)
+		assert.NilError(t, err)
+		assert.Assert(t, res != nil)
+		assert.Equal(t, len(res), 0)
+	})
+
+	t.Run("should stop at the first error", func(t *testing.T) {
+		calls := 0
+		res, err := FilterE([]string{"1", "NaN", "3"}, func(s string) (bool, error) {
+			calls++
+			return positive(s)
+		})
+		assert.ErrorContains(t, err, `parsing "NaN": invalid syntax`)
+		assert.Assert(t, res == nil)
+		assert.Equal(t, calls, 2)
+	})
+}
+
+func TestPartition(t *testing.T) {
+	t.Parallel()
+
+	even := func(n int) bool { return n%2 == 0 }
+
+	tests := []struct {
+		name            string
+		input           []int
+		expectedMatched []int
+		expectedRest    []int
+	}{
+		{name: "should split preserving order", input: []int{1, 2, 3, 4, 6, 5}, expectedMatched: []int{2, 4, 6}, expectedRest: []int{1, 3, 5}},
+		{name: "should return an empty rest when all match", input: []int{2, 4}, expectedMatched: []int{2, 4}, expectedRest: []int{}},
+		{name: "should return an empty matched when none match", input: []int{1, 3}, expectedMatched: []int{}, expectedRest: []int{1, 3}},
+		{name: "should return empty slices for nil input", input: nil, expectedMatched: []int{}, expectedRest: []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matched, rest := Partition(tt.input, even)
+			assert.Assert(t, matched != nil && rest != nil)
+			assert.DeepEqual(t, matched, tt.expectedMatched)
+			assert.DeepEqual(t, rest, tt.expectedRest)
+		})
+	}
+}
+
+func TestReduce(t *testing.T) {
+	t.Parallel()
+
+	sum := func(acc, n int) int { return acc + n }
+
+	tests := []struct {
+		name     string
+		input    []int
+		initial  int
+		expected int
+	}{
+		{name: "should fold all elements", input: []int{1, 2, 3, 4}, initial: 0, expected: 10},
+		{name: "should start from the initial value", input: []int{1, 2}, initial: 10, expected: 13},
+		{name: "should return the initial value for nil input", input: nil, initial: 7, expected: 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, Reduce(tt.input, tt.initial, sum), tt.expected)
+		})
+	}
+
+	t.Run("should fold from left to right", func(t *testing.T) {
+		res := Reduce([]int{1, 2, 3}, "", func(acc string, n int) string {
+			return acc + strconv.Itoa(n)
+		})
+		assert.Equal(t, res, "123")
+	})
+}
+
+func TestReduceE(t *testing.T) {
+	t.Parallel()
+
+	sum := func(acc int, s string) (int, error) {
+		n, err := strconv.Atoi(s)
+		return acc + n, err
+	}
+
+	t.Run("should fold all elements", func(t *testing.T) {
+		res, err := ReduceE([]string{"1", "2", "3"}, 0, sum)
+		assert.NilError(t, err)
+		assert.Equal(t, res, 6)
+	})
+
+	t.Run("should stop at the first error", func(t *testing.T) {
+		calls := 0
+		res, err := ReduceE([]string{"1", "NaN", "3"}, 10, func(acc int, s string) (int, error) {
+			calls++
+			return sum(acc, s)
+		})
+		assert.ErrorContains(t, err, `parsing "NaN": invalid syntax`)
+		assert.Equal(t, res, 0)
+		assert.Equal(t, calls, 2)
+	})
+}
+
+func TestWindows(t *testing.T) {
+	t.Parallel()
+
+	type output struct {
+		res [][]int
+	}
+
+	tests := []struct {
+		name   string
+		input  []int
+		size   int
+		output output
+	}{
+		{
+			name:  "windows of size 2",
+			input: []int{1, 2, 3, 4},
+			size:  2,
+			output: output{
+				res: [][]int{{1, 2}, {2, 3}, {3, 4}},
+			},
+		},
+		{
+			name:  "exact fit",
+			input: []int{1, 2, 3},
+			size:  3,
+			output: output{
+				res: [][]int{{1, 2, 3}},
+			},
+		},
+		{
+			name:  "shorter than window",
+			input: []int{1, 2},
+			size:  3,
+			output: output{
+				res: [][]int{},
+			},
+		},
+		{
+			name:  "non positive size",
+			input: []int{1, 2},
+			size:  0,
+			output: output{
+				res: [][]int{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := Windows(tt.input, tt.size)
+			assert.DeepEqual(t, res, tt.output.res)
+		})
+	}
+
+	t.Run("windows should not share the backing array", func(t *testing.T) {
+		input := []int{1, 2, 3}
+		res := Windows(input, 2)
+		res[0][1] = 42
+
+		assert.DeepEqual(t, input, []int{1, 2, 3})
+		assert.DeepEqual(t, res[1], []int{2, 3})
+	})
+}
+
+func TestChunk(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    []int
+		size     int
+		expected [][]int
+	}{
+		{name: "with a remainder", input: []int{1, 2, 3, 4, 5}, size: 2, expected: [][]int{{1, 2}, {3, 4}, {5}}},
+		{name: "exact fit", input: []int{1, 2, 3, 4}, size: 2, expected: [][]int{{1, 2}, {3, 4}}},
+		{name: "size larger than input", input: []int{1, 2}, size: 5, expected: [][]int{{1, 2}}},
+		{name: "empty input", input: nil, size: 3, expected: [][]int{}},
+		{name: "zero size", input: []int{1, 2}, size: 0, expected: [][]int{}},
+		{name: "negative size", input: []int{1, 2}, size: -1, expected: [][]int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.DeepEqual(t, Chunk(tt.input, tt.size), tt.expected)
+		})
+	}
+
+	t.Run("chunks should not share the backing array", func(t *testing.T) {
+		input := []int{1, 2, 3}
+		res := Chunk(input, 2)
+		res[0] = append(res[0], 42)
+		res[1][0] = 42
+
+		assert.DeepEqual(t, input, []int{1, 2, 3})
+	})
+}
+
+func TestInterleave(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    [][]int
+		expected []int
+	}{
+		{
+			name:     "equal lengths",
+			input:    [][]int{{1, 2, 3}, {10, 20, 30}},
+			expected: []int{1, 10, 2, 20, 3, 30},
+		},
+		{
+			name:     "unequal lengths",
+			input:    [][]int{{1, 2, 3}, {10}, {100, 200}},
+			expected: []int{1, 10, 100, 2, 200, 3},
+		},
+		{
+			name:     "with an empty slice",
+			input:    [][]int{{}, {1, 2}},
+			expected: []int{1, 2},
+		},
+		{
+			name:     "without slices",
+			input:    nil,
+			expected: []int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := Interleave(tt.input...)
+			assert.DeepEqual(t, res, tt.expected)
+		})
+	}
+}
+
+func TestUnique(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    []int
+		expected []int
+	}{
+		{name: "should keep the first occurrences in order", input: []int{3, 1, 3, 2, 1, 3}, expected: []int{3, 1, 2}},
+		{name: "should keep a slice without duplicates", input: []int{1, 2, 3}, expected: []int{1, 2, 3}},
+		{name: "should return an empty slice for nil input", input: nil, expected: []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := Unique(tt.input)
+			assert.Assert(t, res != nil)
+			assert.DeepEqual(t, res, tt.expected)
+		})
+	}
+}
+
+func TestUniqueBy(t *testing.T) {
+	t.Parallel()
+
+	type user struct {
+		ID   int
+		Name string
+	}
+
+	users := []user{{ID: 1, Name: "alice"}, {ID: 2, Name: "bob"}, {ID: 1, Name: "alice-dup"}, {ID: 3, Name: "carol"}}
+	res := UniqueBy(users, func(u user) int { return u.ID })
+	assert.DeepEqual(t, res, []user{{ID: 1, Name: "alice"}, {ID: 2, Name: "bob"}, {ID: 3, Name: "carol"}})
+
+	empty := UniqueBy([]user{}, func(u user) int { return u.ID })
+	assert.Assert(t, empty != nil)
+	assert.Equal(t, len(empty), 0)
+}
+
+func TestSetOperations(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name                 string
+		a                    []string
+		b                    []string
+		expectedUnion        []string
+		expectedIntersection []string
+		expectedDifference   []string
+	}{
+		{
+			name:                 "with overlapping slices",
+			a:                    []string{"read", "write", "read", "admin"},
+			b:                    []string{"delete", "admin", "read", "delete"},
+			expectedUnion:        []string{"read", "write", "admin", "delete"},
+			expectedIntersection: []string{"read", "admin"},
+			expectedDifference:   []string{"write"},
+		},
+		{
+			name:                 "with disjoint slices",
+			a:                    []string{"read"},
+			b:                    []string{"write"},
+			expectedUnion:        []string{"read", "write"},
+			expectedIntersection: []string{},
+			expectedDifference:   []string{"read"},
+		},
+		{
+			name:                 "with empty second slice",
+			a:                    []string{"write", "write"},
+			b:                    nil,
+			expectedUnion:        []string{"write"},
+			expectedIntersection: []string{},
+			expectedDifference:   []string{"write"},
+		},
+		{
+			name:                 "with empty slices",
+			a:                    nil,
+			b:                    nil,
+			expectedUnion:        []string{},
+			expectedIntersection: []string{},
+			expectedDifference:   []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			union := Union(tt.a, tt.b)
+			intersection := Intersection(tt.a, tt.b)
+			difference := Difference(tt.a, tt.b)
+
+			assert.Assert(t, union != nil && intersection != nil && difference != nil)
+			assert.DeepEqual(t, union, tt.expectedUnion)
+			assert.DeepEqual(t, intersection, tt.expectedIntersection)
+			assert.DeepEqual(t, difference, tt.expectedDifference)
+		})
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	t.Parallel()
+
+	type user struct {
+		Name string
+		Role string
+	}
+
+	users := []user{
+		{Name: "alice", Role: "admin"},
+		{Name: "bob", Role: "editor"},
+		{Name: "carol", Role: "admin"},
+		{Name: "dave", Role: "viewer"},
+		{Name: "erin", Role: "editor"},
+	}
+
+	res := GroupBy(users, func(u user) string { return u.Role })
+	assert.DeepEqual(t, res, map[string][]user{
+		"admin":  {{Name: "alice", Role: "admin"}, {Name: "carol", Role: "admin"}},
+		"editor": {{Name: "bob", Role: "editor"}, {Name: "erin", Role: "editor"}},
+		"viewer": {{Name: "dave", Role: "viewer"}},
+	})
+
+	empty := GroupBy(nil, func(u user) string { return u.Role })
+	assert.Assert(t, empty != nil)
+	assert.Equal(t, len(empty), 0)
+}
+
+func TestContainsAndIndexOf(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		input         []string
+		target        string
+		expectedIndex int
+	}{
+		{name: "should find the first occurrence", input: []string{"a", "b", "c", "b"}, target: "b", expectedIndex: 1},
+		{name: "should find the first element", input: []string{"a", "b"}, target: "a", expectedIndex: 0},
+		{name: "should not find a missing element", input: []string{"a", "b"}, target: "z", expectedIndex: -1},
+		{name: "should not find in nil input", input: nil, target: "", expectedIndex: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, IndexOf(tt.input, tt.target), tt.expectedIndex)
+			assert.Equal(t, Contains(tt.input, tt.target), tt.expectedIndex >= 0)
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+
+	type user struct {
+		ID   int
+		Name string
+	}
+
+	users := []user{{ID: 1, Name: "alice"}, {ID: 2, Name: "bob"}, {ID: 3, Name: "bob"}}
+
+	res, ok := Find(users, func(u user) bool { return u.Name == "bob" })
+	assert.Assert(t, ok)
+	assert.DeepEqual(t, res, user{ID: 2, Name: "bob"})
+
+	res, ok = Find(users, func(u user) bool { return u.Name == "carol" })
+	assert.Assert(t, !ok)
+	assert.DeepEqual(t, res, user{})
+}
+
+func TestToMap(t *testing.T) {
+	t.Parallel()
+
+	type user struct {
+		ID   int
+		Name string
+	}
+
+	users := []user{{ID: 1, Name: "alice"}, {ID: 2, Name: "bob"}, {ID: 1, Name: "alice-updated"}}
+
+	res := ToMap(users, func(u user) (int, string) { return u.ID, u.Name })
+	assert.DeepEqual(t, res, map[int]string{1: "alice-updated", 2: "bob"})
+
+	empty := ToMap(nil, func(u user) (int, string) { return u.ID, u.Name })
+	assert.Assert(t, empty != nil)
+	assert.Equal(t, len(empty), 0)
+}
+
+func TestKeyBy(t *testing.T) {
+	t.Parallel()
+
+	type user struct {
+		ID   int
+		Name string
+	}
+
+	users := []user{{ID: 1, Name: "alice"}, {ID: 2, Name: "bob"}, {ID: 1, Name: "alice-updated"}}
+
+	res := KeyBy(users, func(u user) int { return u.ID })
+	assert.DeepEqual(t, res, map[int]user{1: {ID: 1, Name: "alice-updated"}, 2: {ID: 2, Name: "bob"}})
+
+	empty := KeyBy([]user{}, func(u user) int { return u.ID })
+	assert.Assert(t, empty != nil)
+	assert.Equal(t, len(empty), 0)
+}
+
+func TestReverse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    []int
+		expected []int
+	}{
+		{name: "with an even length", input: []int{1, 2, 3, 4}, expected: []int{4, 3, 2, 1}},
+		{name: "with an odd length", input: []int{1, 2, 3}, expected: []int{3, 2, 1}},
+		{name: "with a single element", input: []int{1}, expected: []int{1}},
+		{name: "with nil input", input: nil, expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Reverse(tt.input)
+			assert.DeepEqual(t, tt.input, tt.expected)
+		})
+	}
+}
+
+func TestReversed(t *testing.T) {
+	t.Parallel()
+
+	input := []int{1, 2, 3}
+	assert.DeepEqual(t, Reversed(input), []int{3, 2, 1})
+	assert.DeepEqual(t, input, []int{1, 2, 3})
+
+	assert.DeepEqual(t, Reversed([]int{1}), []int{1})
+
+	empty := Reversed[int](nil)
+	assert.Assert(t, empty != nil)
+	assert.Equal(t, len(empty), 0)
+}
+
+func TestSum(t *testing.T) {
+	t.Parallel()
+
+	type cents int64
+
+	assert.Equal(t, Sum([]int{1, 2, 3}), 6)
+	assert.Equal(t, Sum([]float64{0.5, 1.25}), 1.75)
+	assert.Equal(t, Sum([]cents{1999, 1}), cents(2000))
+	assert.Equal(t, Sum[uint8](nil), uint8(0))
+}
+
+func TestMinMax(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		input       []float64
+		expectedMin float64
+		expectedMax float64
+		expectedOk  bool
+	}{
+		{name: "with several elements", input: []float64{3, -1.5, 7, 0}, expectedMin: -1.5, expectedMax: 7, expectedOk: true},
+		{name: "with a single element", input: []float64{2}, expectedMin: 2, expectedMax: 2, expectedOk: true},
+		{name: "with nil input", input: nil, expectedOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			minimum, ok := Min(tt.input)
+			assert.Equal(t, ok, tt.expectedOk)
+			assert.Equal(t, minimum, tt.expectedMin)
+
+			maximum, ok := Max(tt.input)
+			assert.Equal(t, ok, tt.expectedOk)
+			assert.Equal(t, maximum, tt.expectedMax)
+		})
+	}
+
+	t.Run("should compare strings", func(t *testing.T) {
+		minimum, _ := Min([]string{"pear", "apple", "fig"})
+		maximum, _ := Max([]string{"pear", "apple", "fig"})
+		assert.Equal(t, minimum, "apple")
+		assert.Equal(t, maximum, "pear")
+	})
+}
+
+func TestMinByMaxBy(t *testing.T) {
+	t.Parallel()
+
+	type user struct {
+		Name string
+		Age  int
+	}
+
+	users := []user{{Name: "alice", Age: 30}, {Name: "bob", Age: 25}, {Name: "carol", Age: 41}, {Name: "dave", Age: 25}, {Name: "erin", Age: 41}}
+	age := func(u user) int { return u.Age }
+
+	youngest, ok := MinBy(users, age)
+	assert.Assert(t, ok)
+	assert.Equal(t, youngest, user{Name: "bob", Age: 25})
+
+	oldest, ok := MaxBy(users, age)
+	assert.Assert(t, ok)
+	assert.Equal(t, oldest, user{Name: "carol", Age: 41})
+
+	none, ok := MinBy(nil, age)
+	assert.Assert(t, !ok)
+	assert.Equal(t, none, user{})
+
+	none, ok = MaxBy([]user{}, age)
+	assert.Assert(t, !ok)
+	assert.Equal(t, none, user{})
+}
+
+func TestRemove(t *testing.T) {
+	t.Parallel()
+
+	input := []string{"a", "b", "a", "c", "a"}
+	res := Remove(input, "a")
+
+	assert.DeepEqual(t, res, []string{"b", "c"})
+	assert.DeepEqual(t, input, []string{"a", "b", "a", "c", "a"})
+
+	res = Remove(input, "z")
+	assert.DeepEqual(t, res, input)
+}
+
+func TestRemoveAt(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		index    int
+		expected []int
+	}{
+		{
+			name:     "remove first",
+			index:    0,
+			expected: []int{2, 3},
+		},
+		{
+			name:     "remove middle",
+			index:    1,
+			expected: []int{1, 3},
+		},
+		{
+			name:     "remove last",
+			index:    2,
+			expected: []int{1, 2},
+		},
+		{
+			name:     "negative index",
+			index:    -1,
+			expected: []int{1, 2, 3},
+		},
+		{
+			name:     "index out of range",
+			index:    3,
+			expected: []int{1, 2, 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := []int{1, 2, 3}
+			res := RemoveAt(input, tt.index)
+
+			assert.DeepEqual(t, res, tt.expected)
+			assert.DeepEqual(t, input, []int{1, 2, 3})
+		})
+	}
+}
+
+func TestSliceEqual(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		a        []int
+		b        []int
+		expected bool
+	}{
+		{
+			name:     "equal",
+			a:        []int{1, 2, 3},
+			b:        []int{1, 2, 3},
+			expected: true,
+		},
+		{
+			name:     "nil and empty",
+			a:        nil,
+			b:        []int{},
+			expected: true,
+		},
+		{
+			name:     "different length",
+			a:        []int{1, 2, 3},
+			b:        []int{1, 2},
+			expected: false,
+		},
+		{
+			name:     "element mismatch",
+			a:        []int{1, 2, 3},
+			b:        []int{1, 3, 2},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, SliceEqual(tt.a, tt.b), tt.expected)
+		})
+	}
+}
+
+func TestSliceEqualFunc(t *testing.T) {
+	t.Parallel()
+
+	eq := func(x, y []string) bool {
+		return SliceEqual(x, y)
+	}
+
+	assert.Assert(t, SliceEqualFunc([][]string{{"a"}, {"b", "c"}}, [][]string{{"a"}, {"b", "c"}}, eq))
+	assert.Assert(t, !SliceEqualFunc([][]string{{"a"}}, [][]string{{"a"}, {"b"}}, eq))
+	assert.Assert(t, !SliceEqualFunc([][]string{{"a"}, {"b"}}, [][]string{{"a"}, {"c"}}, eq))
+}